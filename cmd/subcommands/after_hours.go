@@ -0,0 +1,94 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	workStart          int
+	workEnd            int
+	holidays           []string
+	afterHoursTimezone string
+	afterHoursAlert    float64
+	afterHoursMin      int
+)
+
+var AfterHoursCmd = &cobra.Command{
+	Use:   "after-hours <path/to/repo>",
+	Short: "Report commits made outside working hours",
+	Long: heredoc.Doc(`
+        Report the share of each author's commits made outside working hours, on
+        weekends or on holidays, how it evolves month over month, and alert when it
+        rises sharply. Times are in each author's own timezone as recorded in the
+        commit, unless --tz normalizes them.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze after-hours path/to/local/repo
+
+        # Working hours from 8:00 to 17:00, with holidays
+        $ vc-analyze after-hours --start 8 --end 17 --holidays 2024-12-25,2025-01-01 path/to/local/repo
+
+        # Alert on rises of 10 points or more
+        $ vc-analyze after-hours --alert 10 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		if workStart < 0 || workEnd > 24 || workStart >= workEnd {
+			return fmt.Errorf("invalid working hours %d-%d", workStart, workEnd)
+		}
+		for _, holiday := range holidays {
+			if _, err := time.Parse("2006-01-02", holiday); err != nil {
+				return fmt.Errorf("invalid holiday %s, use YYYY-MM-DD", holiday)
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+
+		var loc *time.Location
+		if afterHoursTimezone != "" {
+			var err error
+			loc, err = time.LoadLocation(afterHoursTimezone)
+			if err != nil {
+				return fmt.Errorf("invalid timezone %s: %w", afterHoursTimezone, err)
+			}
+		}
+		analyzer.AnalyzeAfterHours(repoPath, workStart, workEnd, holidays, loc, afterHoursAlert, afterHoursMin, opts)
+		return nil
+	},
+}
+
+func init() {
+	AfterHoursCmd.Flags().IntVar(&workStart, "start", 9, "Hour working time starts at")
+	AfterHoursCmd.Flags().IntVar(&workEnd, "end", 18, "Hour working time ends at")
+	AfterHoursCmd.Flags().StringSliceVar(&holidays, "holidays", nil, "Comma separated holidays as YYYY-MM-DD")
+	AfterHoursCmd.Flags().StringVar(&afterHoursTimezone, "tz", "", "Timezone to normalize commit times to, e.g. UTC or Europe/Berlin")
+	AfterHoursCmd.Flags().Float64Var(&afterHoursAlert, "alert", analyzer.DefaultOffHoursAlert, "Rise in percentage points of the monthly off-hours share that raises an alert")
+	AfterHoursCmd.Flags().IntVar(&afterHoursMin, "min-commits", 5, "Minimum commits in both months for an alert")
+	addTeamFlags(AfterHoursCmd)
+	addAsOfFlags(AfterHoursCmd)
+}
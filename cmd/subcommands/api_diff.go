@@ -0,0 +1,60 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var apiDiffNoColor bool
+
+var APIDiffCmd = &cobra.Command{
+	Use:   "api-diff <old-ref> <new-ref> [path/to/repo]",
+	Short: "Detect exported Go API changes between two revisions",
+	Long: heredoc.Doc(`
+        Compare the exported identifiers, function signatures, struct fields and
+        interface methods of the Go packages at two revisions, and classify each
+        change as compatible or breaking.
+
+        When both revisions carry semantic version tags, breaking changes without
+        a major version bump make the command fail.
+    `),
+	Example: heredoc.Doc(`
+        # Compare two releases of the repository in the current directory
+        $ vc-analyze api-diff v1.2.0 v1.3.0
+
+        # Compare a branch against main
+        $ vc-analyze api-diff main feature/new-api path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("requires an old and a new revision")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 2 {
+			repoPath = args[2]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		if !analyzer.AnalyzeAPIDiff(repoPath, args[0], args[1], apiDiffNoColor) {
+			return errors.New("breaking API change without a major version bump")
+		}
+		return nil
+	},
+}
+
+func init() {
+	APIDiffCmd.Flags().BoolVar(&apiDiffNoColor, "no-color", false, "Disable colored output")
+}
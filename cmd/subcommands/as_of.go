@@ -0,0 +1,46 @@
+package subcommands
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+// Values starting like a date are never taken as a revision, so that a
+// mistyped date is reported as such
+var datePrefix = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}`)
+
+var asOf string
+
+// addAsOfFlags registers the flag analyzing the repository at a past point
+// on cmd
+func addAsOfFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&asOf, "as-of", "", "Analyze the repository as it was at the end of a date (YYYY-MM-DD), at an RFC 3339 time or at a revision")
+}
+
+// applyAsOfFlags makes opts analyze the repository at the point given by the
+// flags, if any. Anything but a date or time is taken as a revision, which
+// must exist in the repository.
+func applyAsOfFlags(repoPath string, opts *analyzer.Options) error {
+	if asOf == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, asOf); err == nil {
+		opts.AsOf = t
+	} else if t, err := time.Parse(dateLayout, asOf); err == nil {
+		// Include the commits of the whole day
+		opts.AsOf = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	} else if datePrefix.MatchString(asOf) {
+		return fmt.Errorf(`as-of "%s" is invalid, use YYYY-MM-DD, RFC 3339 or a revision`, asOf)
+	} else {
+		opts.AsOfRevision = asOf
+	}
+
+	// Resolve the point up front so that an unknown revision fails the command
+	_, err := analyzer.AsOfCommit(repoPath, *opts)
+	return err
+}
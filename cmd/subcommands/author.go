@@ -0,0 +1,63 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	authorDepth int
+	authorLimit int
+)
+
+var AuthorCmd = &cobra.Command{
+	Use:   "author <name-or-email> [path/to/repo]",
+	Short: "Show the profile of a contributor",
+	Long: heredoc.Doc(`
+        Show the full profile of one contributor: commits over time, lines added and
+        deleted, top directories and file types, typical commit size, commit message
+        compliance, most frequent collaborators and the areas they currently own.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze author alice@example.com path/to/local/repo
+
+        # Group directories two levels deep
+        $ vc-analyze author --depth 2 "Alice Smith"
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires an author name or email")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		analyzer.AnalyzeAuthor(repoPath, args[0], authorDepth, authorLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	AuthorCmd.Flags().IntVar(&authorDepth, "depth", 1, "Number of directory levels to group files by")
+	AuthorCmd.Flags().IntVar(&authorLimit, "top", 5, "Number of entries to show per section (0 shows all)")
+	addAsOfFlags(AuthorCmd)
+}
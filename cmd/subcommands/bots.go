@@ -0,0 +1,30 @@
+package subcommands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	excludeBots bool
+	botPatterns []string
+)
+
+// addBotFlags registers the flags controlling bot detection on cmd
+func addBotFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&excludeBots, "exclude-bots", false, "Skip commits authored by bot accounts")
+	cmd.Flags().StringSliceVar(&botPatterns, "bot-pattern", nil, "Additional regular expressions matching bot author names or emails")
+}
+
+// applyBotFlags makes opts skip bot commits when the flags exclude them
+func applyBotFlags(opts *analyzer.Options) error {
+	bots, err := analyzer.NewBotMatcher(botPatterns)
+	if err != nil {
+		return err
+	}
+	if excludeBots {
+		opts.Bots = bots
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	inactiveDays      int
+	protectedBranches []string
+	applyPrune        bool
+	dryRunPrune       bool
+)
+
+var BranchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "Manage the branches of the local repo",
+}
+
+var BranchesPruneCmd = &cobra.Command{
+	Use:   "prune <path/to/repo>",
+	Short: "List or delete stale local branches",
+	Long: heredoc.Doc(`
+        List the local branches which are fully merged into the default branch or have
+        had no commits for longer than the inactivity threshold. With --apply their refs
+        are deleted, with --dry-run the branches that would be deleted are shown instead.
+        The default branch, the checked out branch and branches matching a protected
+        pattern are never pruned. Patterns given with --protected are added to the
+        default ones: main, master, develop and release/*.
+    `),
+	Example: heredoc.Doc(`
+        # List stale branches
+        $ vc-analyze branches prune path/to/local/repo
+
+        # Show what would be deleted with a 30 day threshold
+        $ vc-analyze branches prune --inactive-days 30 --dry-run path/to/local/repo
+
+        # Delete stale branches, also keeping every hotfix branch
+        $ vc-analyze branches prune --apply --protected "hotfix/*" path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		if inactiveDays < 1 {
+			return errors.New("inactive-days must be at least 1")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0]
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+
+		analyzer.AnalyzePruneBranches(repoPath, inactiveDays, protectedBranches, applyPrune, dryRunPrune, opts)
+		return nil
+	},
+}
+
+func init() {
+	BranchesPruneCmd.Flags().IntVar(&inactiveDays, "inactive-days", analyzer.DefaultInactiveDays, "Number of days without commits after which a branch is stale")
+	BranchesPruneCmd.Flags().StringSliceVar(&protectedBranches, "protected", nil, "Glob patterns of branches that are never pruned, in addition to the defaults")
+	BranchesPruneCmd.Flags().BoolVar(&applyPrune, "apply", false, "Delete the local refs of the stale branches")
+	BranchesPruneCmd.Flags().BoolVar(&dryRunPrune, "dry-run", false, "Show the branches that would be deleted without deleting them")
+	addNowFlags(BranchesPruneCmd)
+	BranchesCmd.AddCommand(BranchesPruneCmd)
+}
@@ -15,6 +15,13 @@ var (
 	authorStats  bool
 	commitSize   bool
 	activeBranch bool
+	timeline     bool
+	bucket       string
+	split        string
+	outputFormat string
+	coauthors    bool
+	fractional   bool
+	pairs        bool
 )
 
 var CalcStatsCmd = &cobra.Command{
@@ -25,16 +32,50 @@ var CalcStatsCmd = &cobra.Command{
         # Calculate author statistics
         $ vc-analyze calc-stats --author-stats path/to/local/repo
 
+        # Credit co-authors from Co-authored-by trailers, splitting each commit equally
+        $ vc-analyze calc-stats --author-stats --co-authors --fractional path/to/local/repo
+
+        # Show who pairs with whom
+        $ vc-analyze calc-stats --pairs path/to/local/repo
+
+        # Show which teams pair with each other
+        $ vc-analyze calc-stats --pairs --teams teams.yaml path/to/local/repo
+
+        # Leave out Dependabot, Renovate and other bots, and summarize their activity
+        $ vc-analyze calc-stats --author-stats --exclude-bots path/to/local/repo
+
         # Calculate commit size statistics
         $ vc-analyze calc-stats --commit-size path/to/local/repo
 
         # Calculate branch statistics
         $ vc-analyze calc-stats --active-branch path/to/local/repo
+
+        # Show monthly activity split by top-level directory
+        $ vc-analyze calc-stats --timeline --bucket month --split directory path/to/local/repo
+
+        # Export weekly activity as JSON for plotting
+        $ vc-analyze calc-stats --timeline --format json path/to/local/repo
     `),
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return errors.New("requires a path to the repository")
 		}
+		// Commit size and branch statistics are not kept per author
+		if teamsFile != "" && !authorStats && (commitSize || activeBranch || (timeline && split != analyzer.SplitAuthor)) {
+			return errors.New("--teams needs --author-stats, --pairs or --timeline --split author")
+		}
+		if !timeline {
+			return nil
+		}
+		if bucket != analyzer.PeriodWeek && bucket != analyzer.PeriodMonth {
+			return fmt.Errorf(`bucket "%s" is invalid, use week or month`, bucket)
+		}
+		if split != analyzer.SplitNone && split != analyzer.SplitAuthor && split != analyzer.SplitDirectory {
+			return fmt.Errorf(`split "%s" is invalid, use author or directory`, split)
+		}
+		if outputFormat != "text" && outputFormat != "json" {
+			return fmt.Errorf(`format "%s" is invalid, use text or json`, outputFormat)
+		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -45,18 +86,46 @@ var CalcStatsCmd = &cobra.Command{
 			return fmt.Errorf("repository path does not exist: %s", repoPath)
 		}
 
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyBotFlags(&opts); err != nil {
+			return err
+		}
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+
 		// Check which flag is set and call the appropriate function
-		if authorStats {
+		if authorStats && (coauthors || fractional) {
+			// Call a function to calculate author statistics including co-authors
+			analyzer.AnalyzeCommitCredit(repoPath, fractional, opts)
+		} else if authorStats {
 			// Call a function to calculate author statistics
-			analyzer.AnalyzeCommitHistory(repoPath)
+			analyzer.AnalyzeCommitHistory(repoPath, opts)
 		} else if commitSize {
 			// Call a function to calculate commit size statistics
-			analyzer.AnalyzeCommitSize(repoPath)
+			analyzer.AnalyzeCommitSize(repoPath, opts)
 		} else if activeBranch {
 			//Call function to show branch statistics
-			analyzer.AnalyzeBranchStats(repoPath)
+			analyzer.AnalyzeBranchStats(repoPath, opts)
+		} else if timeline {
+			// Call function to show activity over time
+			analyzer.AnalyzeTimeline(repoPath, bucket, split, outputFormat == "json", opts)
+		} else if pairs {
+			// Call function to show pair programming statistics
+			analyzer.AnalyzePairing(repoPath, opts)
 		} else {
-			return errors.New("no valid flag provided, use --author-stats , --commit-size or --active-branch")
+			return errors.New("no valid flag provided, use --author-stats , --commit-size , --active-branch , --timeline or --pairs")
+		}
+
+		// Summarize what was left out, unless the output is meant for other tools
+		if opts.Bots != nil && !(timeline && outputFormat == "json") {
+			analyzer.AnalyzeBotActivity(repoPath, opts.Bots, opts)
 		}
 
 		return nil
@@ -65,6 +134,17 @@ var CalcStatsCmd = &cobra.Command{
 
 func init() {
 	CalcStatsCmd.Flags().BoolVar(&authorStats, "author-stats", false, "Calculate statistics for each author")
+	CalcStatsCmd.Flags().BoolVar(&coauthors, "co-authors", false, "Also credit co-authors from Co-authored-by trailers in author statistics")
+	CalcStatsCmd.Flags().BoolVar(&fractional, "fractional", false, "Split the credit of each commit equally between its author and co-authors")
+	CalcStatsCmd.Flags().BoolVar(&pairs, "pairs", false, "Show who pairs with whom based on Co-authored-by trailers")
 	CalcStatsCmd.Flags().BoolVar(&commitSize, "commit-size", false, "Calculate the size of commits")
 	CalcStatsCmd.Flags().BoolVar(&activeBranch, "active-branch", false, "Show branch statistics")
+	CalcStatsCmd.Flags().BoolVar(&timeline, "timeline", false, "Show activity per period")
+	CalcStatsCmd.Flags().StringVar(&bucket, "bucket", analyzer.PeriodWeek, "Period of the timeline buckets: week or month")
+	CalcStatsCmd.Flags().StringVar(&split, "split", analyzer.SplitNone, "Split the timeline by author or directory")
+	CalcStatsCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format of the timeline: text or json")
+	addBotFlags(CalcStatsCmd)
+	addTeamFlags(CalcStatsCmd)
+	addNowFlags(CalcStatsCmd)
+	addAsOfFlags(CalcStatsCmd)
 }
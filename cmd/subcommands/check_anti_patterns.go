@@ -11,12 +11,26 @@ import (
     "github.com/adigulalkari/VC-Analyzer/pkg/analyzer" 
 )
 
+var (
+    branchPattern   string
+    branchPrefixes  []string
+    branchMaxLength int
+    requireTicket   bool
+    ticketPattern   string
+)
+
 var AntiPatternsCmd = &cobra.Command{ 
     Use:     "check-anti-patterns <detail>",
     Short:   "Find out the anti-patterns present in your repository",
     Example: heredoc.Doc(`
         Find out the anti-patterns present in your repository
         $ vc-analyze check-anti-patterns path/to/local/repo
+
+        # Ignore commits by Dependabot, Renovate and other bots
+        $ vc-analyze check-anti-patterns --exclude-bots path/to/local/repo
+
+        # Require branch names like feature/ABC-123-short-description
+        $ vc-analyze check-anti-patterns --branch-prefixes feature/,fix/ --require-ticket path/to/local/repo
     `),
     Args: func(cmd *cobra.Command, args []string) error {
         if len(args) < 1 {
@@ -32,9 +46,41 @@ var AntiPatternsCmd = &cobra.Command{
             return fmt.Errorf("repository path does not exist: %s", repoPath)
         }
 
+        var opts analyzer.Options
+        if err := applyAsOfFlags(repoPath, &opts); err != nil {
+            return err
+        }
+        if err := applyBotFlags(&opts); err != nil {
+            return err
+        }
+        if err := applyNowFlags(&opts); err != nil {
+            return err
+        }
+
+        // Branch names are only checked when a naming rule is given
+        ticket := ""
+        if requireTicket {
+            ticket = ticketPattern
+        }
+        policy, err := analyzer.NewBranchNamePolicy(branchPattern, branchPrefixes, branchMaxLength, ticket)
+        if err != nil {
+            return err
+        }
+
         // Call the AnalyzeCommitHistory function
-        analyzer.DetectAntiPatterns(repoPath)
+        analyzer.DetectAntiPatterns(repoPath, policy, opts)
 
         return nil
     },
 }
+
+func init() {
+    AntiPatternsCmd.Flags().StringVar(&branchPattern, "branch-pattern", "", "Regular expression branch names must match")
+    AntiPatternsCmd.Flags().StringSliceVar(&branchPrefixes, "branch-prefixes", nil, "Allowed branch name prefixes, e.g. feature/,fix/")
+    AntiPatternsCmd.Flags().IntVar(&branchMaxLength, "branch-max-length", 0, "Maximum length of branch names, 0 for no limit")
+    AntiPatternsCmd.Flags().BoolVar(&requireTicket, "require-ticket", false, "Require a ticket ID in branch names")
+    AntiPatternsCmd.Flags().StringVar(&ticketPattern, "ticket-pattern", analyzer.DefaultTicketPattern, "Regular expression matching ticket IDs")
+    addBotFlags(AntiPatternsCmd)
+    addNowFlags(AntiPatternsCmd)
+    addAsOfFlags(AntiPatternsCmd)
+}
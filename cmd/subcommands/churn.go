@@ -0,0 +1,66 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	reworkWindowDays int
+	churnDepth       int
+)
+
+var ChurnCmd = &cobra.Command{
+	Use:   "churn <path/to/repo>",
+	Short: "Measure code churn and rework rate",
+	Long: heredoc.Doc(`
+        For each commit, measure how many of its added lines were modified or deleted
+        again within a window, and aggregate the rework rate per author, per directory
+        and per week.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze churn path/to/local/repo
+
+        # Count changes within 14 days as rework
+        $ vc-analyze churn --window 14 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+		window := time.Duration(reworkWindowDays) * 24 * time.Hour
+		analyzer.AnalyzeChurn(repoPath, window, churnDepth, opts)
+		return nil
+	},
+}
+
+func init() {
+	ChurnCmd.Flags().IntVar(&reworkWindowDays, "window", int(analyzer.DefaultReworkWindow/(24*time.Hour)), "Number of days within which changing added lines counts as rework")
+	ChurnCmd.Flags().IntVar(&churnDepth, "depth", 1, "Number of directory levels to group files by")
+	addTeamFlags(ChurnCmd)
+	addAsOfFlags(ChurnCmd)
+}
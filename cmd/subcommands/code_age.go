@@ -0,0 +1,69 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	agePeriod string
+	ageDepth  int
+)
+
+var CodeAgeCmd = &cobra.Command{
+	Use:   "code-age <path/to/repo>",
+	Short: "Report the age of the code at HEAD and how long code survives",
+	Long: heredoc.Doc(`
+        Trace every line at HEAD back to the commit that wrote it and report the
+        distribution of line ages by year or quarter, per directory and per author,
+        along with a survival curve: the share of the lines added in each period
+        still present one, two and more periods later, and at HEAD. Periods are
+        taken in UTC.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze code-age path/to/local/repo
+
+        # Bucket lines by quarter and group directories two levels deep
+        $ vc-analyze code-age --period quarter --depth 2 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		if agePeriod != analyzer.PeriodYear && agePeriod != analyzer.PeriodQuarter {
+			return fmt.Errorf(`period "%s" is invalid, use year or quarter`, agePeriod)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+		analyzer.AnalyzeCodeAge(repoPath, agePeriod, ageDepth, opts)
+		return nil
+	},
+}
+
+func init() {
+	CodeAgeCmd.Flags().StringVar(&agePeriod, "period", analyzer.PeriodYear, "Period to bucket line ages by: year or quarter")
+	CodeAgeCmd.Flags().IntVar(&ageDepth, "depth", 1, "Number of directory levels to group files by")
+	addTeamFlags(CodeAgeCmd)
+	addAsOfFlags(CodeAgeCmd)
+}
@@ -0,0 +1,68 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	staleOwnerDays int
+	uncoveredLimit int
+)
+
+var CodeownersCmd = &cobra.Command{
+	Use:   "codeowners <path/to/repo>",
+	Short: "Validate the CODEOWNERS file against the repository and its history",
+	Long: heredoc.Doc(`
+        Parse the CODEOWNERS file from the root, .github/ or docs/ directory and report
+        files not covered by any rule, rules matching no files at HEAD, owners who have
+        not committed to their owned paths recently, and rules whose declared owners
+        differ from the people actually changing the files.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze codeowners path/to/local/repo
+
+        # Flag owners without commits to their paths in the last 180 days
+        $ vc-analyze codeowners --stale-days 180 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		staleAfter := time.Duration(staleOwnerDays) * 24 * time.Hour
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+
+		analyzer.AnalyzeCodeowners(repoPath, staleAfter, uncoveredLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	CodeownersCmd.Flags().IntVar(&staleOwnerDays, "stale-days", 90, "Number of days without commits after which an owner is considered stale")
+	CodeownersCmd.Flags().IntVar(&uncoveredLimit, "top", 20, "Number of uncovered files to list (0 lists all)")
+	addNowFlags(CodeownersCmd)
+	addAsOfFlags(CodeownersCmd)
+}
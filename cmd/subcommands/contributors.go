@@ -0,0 +1,62 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var retentionQuarters int
+
+var ContributorsCmd = &cobra.Command{
+	Use:   "contributors <path/to/repo>",
+	Short: "Report contributor retention and onboarding",
+	Long: heredoc.Doc(`
+        For each author, show the first and last commit date, tenure, number of active
+        weeks and time from the first to the second commit. Also show what share of the
+        people who first committed in a quarter are still committing in the following
+        quarters.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze contributors path/to/local/repo
+
+        # Show retention up to 4 quarters after the first commit
+        $ vc-analyze contributors --quarters 4 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+
+		analyzer.AnalyzeContributors(repoPath, retentionQuarters, opts)
+		return nil
+	},
+}
+
+func init() {
+	ContributorsCmd.Flags().IntVar(&retentionQuarters, "quarters", 8, "Number of quarters to show retention for")
+	addTeamFlags(ContributorsCmd)
+	addAsOfFlags(ContributorsCmd)
+}
@@ -0,0 +1,63 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	fixPattern  string
+	defectLimit int
+)
+
+var DefectsCmd = &cobra.Command{
+	Use:   "defects <path/to/repo>",
+	Short: "Identify bug-fix commits and the commits that likely introduced the bugs",
+	Long: heredoc.Doc(`
+        Identify bug-fix commits by their message, blame the lines they modified or
+        removed in the parent revision, and report the likely bug-introducing commits
+        along with the defect density per file and per author.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze defects path/to/local/repo
+
+        # Only treat commits referencing a JIRA issue as bug fixes
+        $ vc-analyze defects --fix-pattern 'BUG-[0-9]+' path/to/local/repo
+
+        # Also treat commits referencing an issue number as bug fixes
+        $ vc-analyze defects --fix-pattern '(?i)\bfix|#[0-9]+' path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		analyzer.AnalyzeDefects(repoPath, fixPattern, defectLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	DefectsCmd.Flags().StringVar(&fixPattern, "fix-pattern", analyzer.DefaultFixPattern, "Regular expression matching the messages of bug-fix commits")
+	DefectsCmd.Flags().IntVar(&defectLimit, "top", 10, "Number of entries to show per section (0 shows all)")
+	addAsOfFlags(DefectsCmd)
+}
@@ -9,21 +9,34 @@ import (
 
     "github.com/spf13/cobra"
     "github.com/MakeNowJust/heredoc/v2"
+
+    "github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+    showHotspots  bool
+    showFunctions bool
+    hotspotLimit  int
 )
 
 // Struct to hold commit information
 type CommitInfo struct {
     Hash     string
     Author   string
+    Email    string
     Date     string
     Message  string
     Files    map[string]int // File changes with number of lines added/removed
 }
 
-// Function to gather commit history data
-func getCommitHistory(repoPath string) ([]CommitInfo, error) {
+// Function to gather commit history data, from HEAD or the given revision
+func getCommitHistory(repoPath string, revision string) ([]CommitInfo, error) {
     // Navigate to the repository path
-    cmd := exec.Command("git", "-C", repoPath, "log", "--pretty=format:%H,%an,%ad,%s", "--numstat")
+    gitArgs := []string{"-C", repoPath, "log", "--pretty=format:%H,%an,%ae,%ad,%s", "--numstat"}
+    if revision != "" {
+        gitArgs = append(gitArgs, revision)
+    }
+    cmd := exec.Command("git", gitArgs...)
     output, err := cmd.CombinedOutput()
     if err != nil {
         return nil, fmt.Errorf("failed to run git command: %v", err)
@@ -36,12 +49,13 @@ func getCommitHistory(repoPath string) ([]CommitInfo, error) {
     for _, line := range lines {
         if strings.Contains(line, ",") {
             // New commit entry
-            fields := strings.SplitN(line, ",", 4)
+            fields := strings.SplitN(line, ",", 5)
             currentCommit = &CommitInfo{
                 Hash:    fields[0],
                 Author:  fields[1],
-                Date:    fields[2],
-                Message: fields[3],
+                Email:   fields[2],
+                Date:    fields[3],
+                Message: fields[4],
                 Files:   make(map[string]int),
             }
             commits = append(commits, *currentCommit)
@@ -58,9 +72,12 @@ func getCommitHistory(repoPath string) ([]CommitInfo, error) {
 }
 
 // Function to identify bottlenecks based on commit history
-func detectBottlenecks(commits []CommitInfo) {
+func detectBottlenecks(commits []CommitInfo, bots *analyzer.BotMatcher) {
     fileChangeCounts := make(map[string]int)
     for _, commit := range commits {
+        if bots != nil && bots.IsBot(commit.Author, commit.Email) {
+            continue
+        }
         for file := range commit.Files {
             fileChangeCounts[file]++
         }
@@ -80,11 +97,23 @@ var DetectBottlenecksCmd = &cobra.Command{
     Short: "Find bottlenecks in the commit history of a local repository",
     Example: heredoc.Doc(`
         $ vc-analyze detect-bottlenecks path/to/local/repo
+
+        # Rank files by churn x complexity
+        $ vc-analyze detect-bottlenecks --hotspots --top 20 path/to/local/repo
+
+        # Rank Go functions by the number of commits and lines that changed them
+        $ vc-analyze detect-bottlenecks --functions path/to/local/repo
+
+        # Ignore commits by Dependabot, Renovate and other bots
+        $ vc-analyze detect-bottlenecks --hotspots --exclude-bots path/to/local/repo
     `),
     Args: func(cmd *cobra.Command, args []string) error {
         if len(args) < 1 {
             return errors.New("requires a repository path argument")
         }
+        if teamsFile != "" && !showHotspots {
+            return errors.New("--teams needs --hotspots")
+        }
         return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
@@ -95,15 +124,48 @@ var DetectBottlenecksCmd = &cobra.Command{
             return fmt.Errorf("repository path does not exist: %s", repoPath)
         }
 
-        // Get the commit history for the repository
-        commits, err := getCommitHistory(repoPath)
+        var opts analyzer.Options
+        if err := applyAsOfFlags(repoPath, &opts); err != nil {
+            return err
+        }
+        if err := applyBotFlags(&opts); err != nil {
+            return err
+        }
+        if err := applyTeamFlags(&opts); err != nil {
+            return err
+        }
+
+        if showHotspots {
+            analyzer.AnalyzeHotspots(repoPath, hotspotLimit, opts)
+            return nil
+        }
+        if showFunctions {
+            analyzer.AnalyzeFunctionChurn(repoPath, hotspotLimit, opts)
+            return nil
+        }
+
+        // Get the commit history for the repository, as it was at --as-of
+        revision, err := analyzer.AsOfCommit(repoPath, opts)
+        if err != nil {
+            return err
+        }
+        commits, err := getCommitHistory(repoPath, revision)
         if err != nil {
             return fmt.Errorf("error fetching commit history: %v", err)
         }
 
         // Detect bottlenecks based on the commit history
-        detectBottlenecks(commits)
+        detectBottlenecks(commits, opts.Bots)
 
         return nil
     },
 }
+
+func init() {
+    DetectBottlenecksCmd.Flags().BoolVar(&showHotspots, "hotspots", false, "Rank files by churn multiplied by complexity at HEAD")
+    DetectBottlenecksCmd.Flags().BoolVar(&showFunctions, "functions", false, "Rank Go functions by how often and how much they change")
+    DetectBottlenecksCmd.Flags().IntVar(&hotspotLimit, "top", 10, "Number of hotspots or functions to show (0 shows all)")
+    addBotFlags(DetectBottlenecksCmd)
+    addTeamFlags(DetectBottlenecksCmd)
+    addAsOfFlags(DetectBottlenecksCmd)
+}
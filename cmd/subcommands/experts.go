@@ -0,0 +1,74 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	halfLifeDays int
+	expertLimit  int
+)
+
+var ExpertsCmd = &cobra.Command{
+	Use:   "experts <path-or-glob> [path/to/repo]",
+	Short: "Find the contributors who know a file or directory best",
+	Long: heredoc.Doc(`
+        Rank the contributors with the deepest knowledge of the files matching a path
+        or glob, combining their commits, weighted so that recent commits count more
+        than old ones, with the lines still blamed on them at HEAD.
+    `),
+	Example: heredoc.Doc(`
+        # Who knows the analyzer package best?
+        $ vc-analyze experts pkg/analyzer path/to/local/repo
+
+        # Only consider Go files and halve the weight of commits every 90 days
+        $ vc-analyze experts --half-life 90 'pkg/**/*.go'
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path or glob")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+		halfLife := time.Duration(halfLifeDays) * 24 * time.Hour
+		analyzer.AnalyzeExperts(repoPath, args[0], halfLife, expertLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	ExpertsCmd.Flags().IntVar(&halfLifeDays, "half-life", int(analyzer.DefaultKnowledgeHalfLife/(24*time.Hour)), "Number of days after which the weight of a commit halves")
+	ExpertsCmd.Flags().IntVar(&expertLimit, "top", 5, "Number of experts to show (0 shows all)")
+	addTeamFlags(ExpertsCmd)
+	addNowFlags(ExpertsCmd)
+	addAsOfFlags(ExpertsCmd)
+}
@@ -0,0 +1,58 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var FileHistoryCmd = &cobra.Command{
+	Use:   "file-history <path> [path/to/repo]",
+	Short: "Show every commit that touched a file, following renames",
+	Long: heredoc.Doc(`
+        Show every commit that touched a file with its author, date and the lines it
+        added and removed, following the file across renames and moves. Summarize the
+        number of authors, how often the file changes and its age.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze file-history pkg/analyzer/hotspots.go path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a file path")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+
+		analyzer.AnalyzeFileHistory(repoPath, args[0], opts)
+		return nil
+	},
+}
+
+func init() {
+	addNowFlags(FileHistoryCmd)
+	addAsOfFlags(FileHistoryCmd)
+}
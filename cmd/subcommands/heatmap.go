@@ -0,0 +1,76 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	heatmapTimezone string
+	heatmapLimit    int
+	heatmapNoColor  bool
+)
+
+var HeatmapCmd = &cobra.Command{
+	Use:   "heatmap <path/to/repo>",
+	Short: "Show commit activity by hour of day and day of week",
+	Long: heredoc.Doc(`
+        Print a heatmap of when commits are made, by hour of day and day of week, for
+        the whole repository and for its most active authors. Times are in each author's
+        own timezone as recorded in the commit, unless --tz normalizes them.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze heatmap path/to/local/repo
+
+        # Normalize all commit times to UTC
+        $ vc-analyze heatmap --tz UTC path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		if err := applyTeamFlags(&opts); err != nil {
+			return err
+		}
+
+		var loc *time.Location
+		if heatmapTimezone != "" {
+			var err error
+			loc, err = time.LoadLocation(heatmapTimezone)
+			if err != nil {
+				return fmt.Errorf("invalid timezone %s: %w", heatmapTimezone, err)
+			}
+		}
+		analyzer.AnalyzeHeatmap(repoPath, loc, heatmapLimit, heatmapNoColor, opts)
+		return nil
+	},
+}
+
+func init() {
+	HeatmapCmd.Flags().StringVar(&heatmapTimezone, "tz", "", "Timezone to normalize commit times to, e.g. UTC or Europe/Berlin")
+	HeatmapCmd.Flags().IntVar(&heatmapLimit, "top", 5, "Number of authors to show a heatmap for (0 shows all)")
+	HeatmapCmd.Flags().BoolVar(&heatmapNoColor, "no-color", false, "Disable color output")
+	addTeamFlags(HeatmapCmd)
+	addAsOfFlags(HeatmapCmd)
+}
@@ -0,0 +1,61 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var shapeLimit int
+
+var HistoryShapeCmd = &cobra.Command{
+	Use:   "history-shape <path/to/repo>",
+	Short: "Analyze how branches are merged into the current branch",
+	Long: heredoc.Doc(`
+        Report how branches are integrated into the checked out branch: the share of
+        merge commits versus linear history, squash merges, back-merges of the branch
+        into feature branches, criss-cross merges and foxtrot merges that swapped the
+        first-parent history.
+
+        Merges of the branch are recognized by their default messages, by their parents
+        and by the remote-tracking branches of the branch. With a detached HEAD, the
+        branch is any local branch at HEAD, or main and master.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze history-shape path/to/local/repo
+
+        # List every back-merge, criss-cross and foxtrot merge
+        $ vc-analyze history-shape --top 0 path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a path to the repository")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := args[0] // Get the repository path from the arguments
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+		analyzer.AnalyzeHistoryShape(repoPath, shapeLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	HistoryShapeCmd.Flags().IntVar(&shapeLimit, "top", 10, "Number of merges to list per kind (0 shows all)")
+	addAsOfFlags(HistoryShapeCmd)
+}
@@ -0,0 +1,41 @@
+package subcommands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+// Layout of dates given on the command line
+const dateLayout = "2006-01-02"
+
+var nowFlag string
+
+// addNowFlags registers the flag pinning the reference time on cmd
+func addNowFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&nowFlag, "now", "", `Time to measure ages and activity against, as YYYY-MM-DD, RFC 3339 or "latest" for the date of the latest commit (default current time)`)
+}
+
+// applyNowFlags makes opts measure ages and activity against the reference
+// time given by the flags, if any
+func applyNowFlags(opts *analyzer.Options) error {
+	switch nowFlag {
+	case "":
+		return nil
+	case "latest":
+		opts.NowLatest = true
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, nowFlag); err == nil {
+		opts.Now = t
+		return nil
+	}
+	if t, err := time.Parse(dateLayout, nowFlag); err == nil {
+		opts.Now = t
+		return nil
+	}
+	return fmt.Errorf(`now "%s" is invalid, use YYYY-MM-DD, RFC 3339 or latest`, nowFlag)
+}
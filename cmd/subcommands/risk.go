@@ -0,0 +1,61 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var riskFixPattern string
+
+var RiskCmd = &cobra.Command{
+	Use:   "risk <rev-range> [path/to/repo]",
+	Short: "Score the risk of a set of commits",
+	Long: heredoc.Doc(`
+        Score the commits of a revision range, such as a pull request, from 0 to 100
+        based on the size of the change, the hotspot status of the touched files,
+        the number of owners of those files, the authors' prior experience with them
+        and their historical defect density. Every factor is measured on the history
+        leading up to the base of the range. In a range such as main...feature the
+        base is the merge base of both revisions.
+    `),
+	Example: heredoc.Doc(`
+        # Score the commits of a feature branch
+        $ vc-analyze risk main..feature path/to/local/repo
+
+        # Score a pull request branch against the point it forked from main
+        $ vc-analyze risk main...feature path/to/local/repo
+
+        # Score a single commit of the repository in the current directory
+        $ vc-analyze risk HEAD
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a revision range")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		analyzer.AnalyzeRisk(repoPath, args[0], riskFixPattern, analyzer.Options{})
+		return nil
+	},
+}
+
+func init() {
+	RiskCmd.Flags().StringVar(&riskFixPattern, "fix-pattern", analyzer.DefaultFixPattern, "Regular expression matching the messages of bug-fix commits")
+}
@@ -0,0 +1,64 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var (
+	noCodeowners  bool
+	reviewerLimit int
+)
+
+var SuggestReviewersCmd = &cobra.Command{
+	Use:   "suggest-reviewers <rev-range> [path/to/repo]",
+	Short: "Suggest reviewers for a set of commits based on file ownership",
+	Long: heredoc.Doc(`
+        Rank the people with the most recent and most substantial history on the files
+        touched by a revision range, excluding the authors of the change. Code owners
+        of the touched files are listed first when the repository has a CODEOWNERS file.
+    `),
+	Example: heredoc.Doc(`
+        $ vc-analyze suggest-reviewers main..feature path/to/local/repo
+
+        # Ignore the CODEOWNERS file
+        $ vc-analyze suggest-reviewers --no-codeowners main..feature
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a revision range")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyNowFlags(&opts); err != nil {
+			return err
+		}
+
+		analyzer.AnalyzeReviewers(repoPath, args[0], !noCodeowners, reviewerLimit, opts)
+		return nil
+	},
+}
+
+func init() {
+	SuggestReviewersCmd.Flags().BoolVar(&noCodeowners, "no-codeowners", false, "Ignore the CODEOWNERS file")
+	SuggestReviewersCmd.Flags().IntVar(&reviewerLimit, "top", 5, "Number of reviewers to suggest (0 shows all)")
+	addNowFlags(SuggestReviewersCmd)
+}
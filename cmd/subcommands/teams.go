@@ -0,0 +1,95 @@
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/adigulalkari/VC-Analyzer/pkg/analyzer"
+)
+
+var teamsFile string
+
+// addTeamFlags registers the flag aggregating authors by team on cmd
+func addTeamFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&teamsFile, "teams", "", "YAML file mapping teams to members and owned directories, to aggregate authors by team")
+}
+
+// applyTeamFlags makes opts aggregate authors by the teams in the file given
+// by the flags, if any
+func applyTeamFlags(opts *analyzer.Options) error {
+	if teamsFile == "" {
+		return nil
+	}
+	mapping, err := analyzer.LoadTeams(teamsFile)
+	if err != nil {
+		return err
+	}
+	opts.Teams = mapping
+	return nil
+}
+
+var TeamsCmd = &cobra.Command{
+	Use:   "teams <teams-file> [path/to/repo]",
+	Short: "Aggregate activity by team and find cross-team changes",
+	Long: heredoc.Doc(`
+        Aggregate commits and changed lines by team, using a YAML file that maps team
+        names to member emails, email globs or author names, and optionally to the
+        directories they own. Changes a team makes to directories owned by another team
+        are reported as cross-team changes.
+
+        The same file can be passed with --teams to calc-stats, churn, code-age,
+        experts, heatmap, after-hours, contributors and detect-bottlenecks --hotspots
+        to aggregate their reports by team.
+    `),
+	Example: heredoc.Doc(`
+        # teams.yaml:
+        #   platform:
+        #     members: [alice@example.com, "*@infra.example.com"]
+        #     directories: [infra, pkg/platform]
+        #   web:
+        #     members: [Bob]
+        #     directories: [web]
+        $ vc-analyze teams teams.yaml path/to/local/repo
+
+        # Author statistics per team
+        $ vc-analyze calc-stats --author-stats --teams teams.yaml path/to/local/repo
+    `),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a teams file")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoPath := "."
+		if len(args) > 1 {
+			repoPath = args[1]
+		}
+
+		// Check if the repository exists
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			return fmt.Errorf("repository path does not exist: %s", repoPath)
+		}
+
+		var opts analyzer.Options
+		if err := applyAsOfFlags(repoPath, &opts); err != nil {
+			return err
+		}
+
+		mapping, err := analyzer.LoadTeams(args[0])
+		if err != nil {
+			return err
+		}
+		opts.Teams = mapping
+		analyzer.AnalyzeTeams(repoPath, opts)
+		return nil
+	},
+}
+
+func init() {
+	addAsOfFlags(TeamsCmd)
+}
@@ -26,6 +26,22 @@ func init() {
     rootCmd.AddCommand(subcommands.CalcStatsCmd)
     rootCmd.AddCommand(subcommands.AntiPatternsCmd)
     rootCmd.AddCommand(subcommands.DetectBottlenecksCmd)
+    rootCmd.AddCommand(subcommands.APIDiffCmd)
+    rootCmd.AddCommand(subcommands.DefectsCmd)
+    rootCmd.AddCommand(subcommands.RiskCmd)
+    rootCmd.AddCommand(subcommands.SuggestReviewersCmd)
+    rootCmd.AddCommand(subcommands.ExpertsCmd)
+    rootCmd.AddCommand(subcommands.CodeownersCmd)
+    rootCmd.AddCommand(subcommands.CodeAgeCmd)
+    rootCmd.AddCommand(subcommands.ChurnCmd)
+    rootCmd.AddCommand(subcommands.HeatmapCmd)
+    rootCmd.AddCommand(subcommands.AfterHoursCmd)
+    rootCmd.AddCommand(subcommands.ContributorsCmd)
+    rootCmd.AddCommand(subcommands.AuthorCmd)
+    rootCmd.AddCommand(subcommands.FileHistoryCmd)
+    rootCmd.AddCommand(subcommands.TeamsCmd)
+    rootCmd.AddCommand(subcommands.HistoryShapeCmd)
+    rootCmd.AddCommand(subcommands.BranchesCmd)
 }
 
 func main() {
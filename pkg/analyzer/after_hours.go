@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Default threshold, in percentage points, for alerting on a month over month
+// rise of an author's off-hours share
+const DefaultOffHoursAlert = 20
+
+// workSchedule describes regular working time: hours from Start up to End on
+// weekdays, except on holidays given as YYYY-MM-DD dates
+type workSchedule struct {
+	Start    int
+	End      int
+	Holidays map[string]bool
+}
+
+// offHoursStats counts the commits of an author or a period that were made
+// outside working time. Each such commit is counted once, as a holiday,
+// weekend or after-hours commit in that order.
+type offHoursStats struct {
+	Name       string
+	Commits    int
+	AfterHours int
+	Weekend    int
+	Holiday    int
+}
+
+// Outside returns the number of commits made outside working time
+func (s offHoursStats) Outside() int {
+	return s.AfterHours + s.Weekend + s.Holiday
+}
+
+// Share returns the share of commits made outside working time
+func (s offHoursStats) Share() float64 {
+	if s.Commits == 0 {
+		return 0
+	}
+	return float64(s.Outside()) / float64(s.Commits)
+}
+
+func (s *offHoursStats) add(schedule workSchedule, when time.Time) {
+	s.Commits++
+	switch {
+	case schedule.Holidays[when.Format("2006-01-02")]:
+		s.Holiday++
+	case when.Weekday() == time.Saturday || when.Weekday() == time.Sunday:
+		s.Weekend++
+	case when.Hour() < schedule.Start || when.Hour() >= schedule.End:
+		s.AfterHours++
+	}
+}
+
+type offHoursAlert struct {
+	Author   string
+	Month    string
+	Previous float64
+	Current  float64
+}
+
+type offHoursReport struct {
+	Total   offHoursStats
+	Authors []offHoursStats
+	Months  []string
+	Trends  map[string][]offHoursStats // Per author, one entry per month
+	Alerts  []offHoursAlert
+}
+
+// AnalyzeAfterHours reports how many commits to the given repository each
+// author makes outside working hours from start up to end on weekdays and on
+// the given YYYY-MM-DD holidays, and alerts on sharp monthly rises
+func AnalyzeAfterHours(repoPath string, start int, end int, holidays []string, loc *time.Location, threshold float64, minCommits int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	schedule := workSchedule{Start: start, End: end, Holidays: make(map[string]bool)}
+	for _, holiday := range holidays {
+		schedule.Holidays[holiday] = true
+	}
+	afterHours(repo, schedule, loc, threshold, minCommits, opts)
+}
+
+func afterHours(repo *git.Repository, schedule workSchedule, loc *time.Location, threshold float64, minCommits int, opts Options) {
+	report, err := getAfterHours(repo, schedule, loc, threshold, minCommits, opts)
+	if err != nil {
+		log.Fatalf("Error getting after-hours activity: %v", err)
+	}
+	printAfterHours(report, schedule, threshold)
+}
+
+// getAfterHours classifies the author date of every commit against schedule,
+// in each author's own timezone unless loc is given, per author and per month.
+// An alert is raised when an author's off-hours share rises by at least
+// threshold percentage points from one month to the next, provided both
+// months have at least minCommits commits.
+func getAfterHours(repo *git.Repository, schedule workSchedule, loc *time.Location, threshold float64, minCommits int, opts Options) (*offHoursReport, error) {
+	report := &offHoursReport{Total: offHoursStats{Name: "Total"}, Trends: make(map[string][]offHoursStats)}
+	authors := make(map[string]*offHoursStats)
+	monthly := make(map[string]map[string]*offHoursStats)
+	var first, last time.Time
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		when := c.Author.When
+		if loc != nil {
+			when = when.In(loc)
+		}
+		// Months are keyed by the local calendar month, so the range is kept
+		// as wall-clock month starts rather than instants.
+		year, mon, _ := when.Date()
+		start := time.Date(year, mon, 1, 0, 0, 0, 0, time.UTC)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+
+		name := opts.authorName(c.Author.Name, c.Author.Email)
+		s, ok := authors[name]
+		if !ok {
+			s = &offHoursStats{Name: name}
+			authors[name] = s
+			monthly[name] = make(map[string]*offHoursStats)
+		}
+		month := periodKey(when, PeriodMonth)
+		m, ok := monthly[name][month]
+		if !ok {
+			m = &offHoursStats{Name: month}
+			monthly[name][month] = m
+		}
+		for _, stats := range []*offHoursStats{&report.Total, s, m} {
+			stats.add(schedule, when)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if first.IsZero() {
+		return report, nil
+	}
+
+	report.Months = periodRange(first, last, PeriodMonth)
+	for name, s := range authors {
+		report.Authors = append(report.Authors, *s)
+
+		var trend []offHoursStats
+		for _, month := range report.Months {
+			m := offHoursStats{Name: month}
+			if stats, ok := monthly[name][month]; ok {
+				m = *stats
+			}
+			if n := len(trend); n > 0 {
+				prev := trend[n-1]
+				rise := (m.Share() - prev.Share()) * 100
+				if prev.Commits >= minCommits && m.Commits >= minCommits && rise >= threshold {
+					report.Alerts = append(report.Alerts, offHoursAlert{Author: name, Month: month, Previous: prev.Share(), Current: m.Share()})
+				}
+			}
+			trend = append(trend, m)
+		}
+		report.Trends[name] = trend
+	}
+
+	sort.Slice(report.Authors, func(i, j int) bool {
+		si, sj := report.Authors[i].Share(), report.Authors[j].Share()
+		if si != sj {
+			return si > sj
+		}
+		return report.Authors[i].Name < report.Authors[j].Name
+	})
+	sort.Slice(report.Alerts, func(i, j int) bool {
+		if report.Alerts[i].Month != report.Alerts[j].Month {
+			return report.Alerts[i].Month > report.Alerts[j].Month
+		}
+		return report.Alerts[i].Author < report.Alerts[j].Author
+	})
+	return report, nil
+}
+
+func printOffHoursStats(s offHoursStats) {
+	fmt.Printf("%s: %d of %d commits outside working hours (%.1f%%): %d after hours, %d on weekends, %d on holidays\n",
+		s.Name, s.Outside(), s.Commits, s.Share()*100, s.AfterHours, s.Weekend, s.Holiday)
+}
+
+func printAfterHours(report *offHoursReport, schedule workSchedule, threshold float64) {
+	fmt.Printf("After-hours activity (working hours %02d:00-%02d:00 on weekdays, %d holidays):\n\n", schedule.Start, schedule.End, len(schedule.Holidays))
+	printOffHoursStats(report.Total)
+	if len(report.Authors) == 0 {
+		return
+	}
+
+	fmt.Println("\nBy author:")
+	for _, s := range report.Authors {
+		printOffHoursStats(s)
+	}
+
+	fmt.Printf("\nMonthly share outside working hours, from %s to %s:\n", report.Months[0], report.Months[len(report.Months)-1])
+	for _, s := range report.Authors {
+		var shares []int
+		for _, m := range report.Trends[s.Name] {
+			shares = append(shares, int(m.Share()*100))
+		}
+		latest := report.Trends[s.Name][len(shares)-1]
+		fmt.Printf("%s %s (latest %.1f%%)\n", sparkline(shares), s.Name, latest.Share()*100)
+	}
+
+	fmt.Printf("\nAlerts (rise of %.0f points or more month over month):\n", threshold)
+	if len(report.Alerts) == 0 {
+		fmt.Println("None")
+	}
+	for _, a := range report.Alerts {
+		fmt.Printf("%s: %s rose from %.1f%% to %.1f%% outside working hours\n", a.Month, a.Author, a.Previous*100, a.Current*100)
+	}
+}
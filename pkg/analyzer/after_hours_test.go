@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetAfterHours(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// Alice works office hours in January 2024, then mostly late in February
+	var times []time.Time
+	for day := 8; day <= 12; day++ {
+		times = append(times, time.Date(2024, 1, day, 10, 0, 0, 0, time.UTC))
+	}
+	for day := 5; day <= 9; day++ {
+		hour := 22
+		if day > 7 {
+			hour = 11
+		}
+		times = append(times, time.Date(2024, 2, day, hour, 0, 0, 0, time.UTC))
+	}
+	// A Saturday and a holiday
+	times = append(times, time.Date(2024, 2, 10, 11, 0, 0, 0, time.UTC), time.Date(2024, 2, 14, 11, 0, 0, 0, time.UTC))
+	for i, when := range times {
+		alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: when}
+		if _, err := commitFiles(repo, "update", alice, map[string]string{"a.txt": fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	schedule := workSchedule{Start: 9, End: 18, Holidays: map[string]bool{"2024-02-14": true}}
+	report, err := getAfterHours(repo, schedule, nil, DefaultOffHoursAlert, 5, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get after-hours activity: %v", err)
+	}
+
+	expected := offHoursStats{Name: "Total", Commits: 12, AfterHours: 3, Weekend: 1, Holiday: 1}
+	if report.Total != expected {
+		t.Errorf("got %+v, want %+v", report.Total, expected)
+	}
+	trend := report.Trends["Alice"]
+	if len(trend) != 2 || trend[0].Outside() != 0 || trend[1].Outside() != 5 {
+		t.Errorf("Expected 0 then 5 commits outside working hours, got %+v", trend)
+	}
+	if len(report.Alerts) != 1 || report.Alerts[0].Month != "2024-02" || report.Alerts[0].Author != "Alice" {
+		t.Errorf("Expected an alert for Alice in 2024-02, got %+v", report.Alerts)
+	}
+
+	// Not enough commits in January to compare against
+	report, err = getAfterHours(repo, schedule, nil, DefaultOffHoursAlert, 6, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get after-hours activity: %v", err)
+	}
+	if len(report.Alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", report.Alerts)
+	}
+}
+
+func TestGetAfterHoursMonthBoundary(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// Still January 31 in New York, already February 1 in UTC
+	newYork := time.FixedZone("EST", -5*60*60)
+	times := []time.Time{
+		time.Date(2024, 1, 10, 10, 0, 0, 0, newYork),
+		time.Date(2024, 1, 31, 22, 0, 0, 0, newYork),
+	}
+	for i, when := range times {
+		bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: when}
+		if _, err := commitFiles(repo, "update", bob, map[string]string{"b.txt": fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	report, err := getAfterHours(repo, workSchedule{Start: 9, End: 18}, nil, DefaultOffHoursAlert, 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get after-hours activity: %v", err)
+	}
+	if len(report.Months) != 1 || report.Months[0] != "2024-01" {
+		t.Fatalf("Expected only 2024-01, got %v", report.Months)
+	}
+	if trend := report.Trends["Bob"]; len(trend) != 1 || trend[0].Commits != 2 || trend[0].AfterHours != 1 {
+		t.Errorf("Expected both commits in January, got %+v", trend)
+	}
+}
@@ -3,70 +3,32 @@ package analyzer
 import (
     "fmt"
     "log"
+    "sort"
     "time"
 
     "github.com/go-git/go-git/v5"
     "github.com/go-git/go-git/v5/plumbing/object"
 )
 
-// DetectAntiPatterns looks for common version control anti-patterns.
-func DetectAntiPatterns(repoPath string) {
+// DetectAntiPatterns looks for common version control anti-patterns. Branch
+// names are only checked when a naming policy is given.
+func DetectAntiPatterns(repoPath string, policy *BranchNamePolicy, opts Options) {
     // Open the Git repository
     repo, err := git.PlainOpen(repoPath)
     if err != nil {
         log.Fatalf("Error opening repository: %v", err)
     }
 
-    // Get the HEAD reference
-    ref, err := repo.Head()
+    // Time the gap since the last commit and the age of offending branches
+    // are measured against
+    now, err := referenceTime(repo, opts)
     if err != nil {
-        log.Fatalf("Error getting repository HEAD: %v", err)
-    }
-
-    // Get the commit history
-    commitIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
-    if err != nil {
-        log.Fatalf("Error getting commit history: %v", err)
+        log.Fatalf("Error getting reference time: %v", err)
     }
 
     fmt.Println("Detecting anti-patterns...")
 
-    // Initialize anti-pattern counters
-    var largeCommitsCount int
-    var forcePushesDetected bool
-    var infrequentCommits bool
-
-    // Iterate through the commits
-    err = commitIter.ForEach(func(c *object.Commit) error {
-        // Detect large commits
-        if len(c.Message) > 1000 {
-            largeCommitsCount++
-        }
-
-        // Simulate detecting force pushes (for demo purposes)
-        // You can improve this logic by tracking ref changes
-        if c.NumParents() > 1 {
-            forcePushesDetected = true
-        }
-    // FIXME: The logic here needs to be improved 
-
-        return nil
-    })
-    if err != nil {
-        log.Fatalf("Error iterating through commits: %v", err)
-    }
-
-    // Simulate checking for infrequent commits
-    // Check the time between the most recent and older commits
-    recentCommit, _ := repo.CommitObject(ref.Hash())
-    recentTime := recentCommit.Committer.When
-
-    err = commitIter.ForEach(func(c *object.Commit) error {
-        if recentTime.Sub(c.Committer.When) > (7 * 24 * time.Hour) {
-            infrequentCommits = true
-        }
-        return nil
-    })
+    largeCommitsCount, infrequentCommits, err := getCommitPatterns(repo, now, opts)
     if err != nil {
         log.Fatalf("Error iterating through commits: %v", err)
     }
@@ -78,10 +40,25 @@ func DetectAntiPatterns(repoPath string) {
         fmt.Println("No large commits detected.")
     }
 
-    if forcePushesDetected {
-        fmt.Println("Force pushes detected.")
+    // Merge commits are a normal way of integrating branches. Force pushes
+    // leave no trace in the history, but foxtrot merges rewrite the
+    // first-parent history of the branch in a similar way.
+    shape, err := getHistoryShape(repo, false, opts)
+    if err != nil {
+        log.Fatalf("Error getting history shape: %v", err)
+    }
+    if len(shape.Foxtrots) > 0 {
+        fmt.Printf("Detected %d foxtrot merge(s) that swapped the first-parent history.\n", len(shape.Foxtrots))
     } else {
-        fmt.Println("No force pushes detected.")
+        fmt.Println("No foxtrot merges detected.")
+    }
+
+    if policy != nil {
+        offending, err := getBranchNameViolations(repo, policy, opts)
+        if err != nil {
+            log.Fatalf("Error checking branch names: %v", err)
+        }
+        printBranchNameViolations(offending, now)
     }
 
     if infrequentCommits {
@@ -92,3 +69,45 @@ func DetectAntiPatterns(repoPath string) {
 
     fmt.Println("Anti-pattern detection complete.")
 }
+
+// getCommitPatterns counts the large commits and checks for infrequent
+// commits in the history up to now. Commits made after now are not part of
+// the history being checked.
+func getCommitPatterns(repo *git.Repository, now time.Time, opts Options) (int, bool, error) {
+    var largeCommitsCount int
+    var commitTimes []time.Time
+
+    err := forEachCommit(repo, opts, func(c *object.Commit) error {
+        if c.Committer.When.After(now) {
+            return nil
+        }
+
+        // Detect large commits
+        if len(c.Message) > 1000 {
+            largeCommitsCount++
+        }
+
+        commitTimes = append(commitTimes, c.Committer.When)
+        return nil
+    })
+    if err != nil {
+        return 0, false, err
+    }
+    return largeCommitsCount, hasInfrequentCommits(commitTimes, now, 7*24*time.Hour), nil
+}
+
+// hasInfrequentCommits reports whether more than gap passed between two
+// consecutive commits, or between the latest commit and now
+func hasInfrequentCommits(commitTimes []time.Time, now time.Time, gap time.Duration) bool {
+    sort.Slice(commitTimes, func(i, j int) bool {
+        return commitTimes[i].After(commitTimes[j])
+    })
+    previous := now
+    for _, t := range commitTimes {
+        if previous.Sub(t) > gap {
+            return true
+        }
+        previous = t
+    }
+    return false
+}
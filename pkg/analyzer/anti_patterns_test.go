@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestHasInfrequentCommits(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		commits  []time.Time
+		now      time.Time
+		expected bool
+	}{
+		{"regular", []time.Time{start, start.Add(3 * day), start.Add(6 * day)}, start.Add(8 * day), false},
+		{"gap between commits", []time.Time{start.Add(9 * day), start}, start.Add(10 * day), true},
+		{"gap since last commit", []time.Time{start, start.Add(2 * day)}, start.Add(10 * day), true},
+		{"unordered", []time.Time{start, start.Add(12 * day), start.Add(6 * day)}, start.Add(12 * day), false},
+		{"no commits", nil, start, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasInfrequentCommits(tt.commits, tt.now, 7*day); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetCommitPatternsPinned(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	day := 24 * time.Hour
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, offset := range []int{0, 3, 6, 30} {
+		alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start.AddDate(0, 0, offset)}
+		if _, err := commitFiles(repo, "commit", alice, map[string]string{"a.txt": strconv.Itoa(i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected bool
+	}{
+		// The commit 30 days in is after the reference time
+		{"before the last commit", Options{Now: start.Add(8 * day)}, false},
+		{"gap since the last commit", Options{Now: start.Add(20 * day)}, true},
+		{"latest commit", Options{NowLatest: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := referenceTime(repo, tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to get reference time: %v", err)
+			}
+			_, infrequent, err := getCommitPatterns(repo, now, tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to get commit patterns: %v", err)
+			}
+			if infrequent != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, infrequent)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// packageAPI maps a description of every exported identifier of a package,
+// e.g. "func New" or "field Config.Name", to its type or signature
+type packageAPI map[string]string
+
+type apiChange struct {
+	Package  string
+	Item     string
+	Change   string // "added", "removed" or "changed"
+	Breaking bool
+}
+
+// AnalyzeAPIDiff compares the exported Go API of the given repository between
+// two revisions. It returns false when the API changed in a breaking way
+// without the version tags of the revisions bumping the major version.
+func AnalyzeAPIDiff(repoPath string, oldRev string, newRev string, noColor bool) bool {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	return apiDiff(repo, oldRev, newRev, noColor)
+}
+
+func apiDiff(repo *git.Repository, oldRev string, newRev string, noColor bool) bool {
+	changes, skipped, err := getAPIChanges(repo, oldRev, newRev)
+	if err != nil {
+		log.Fatalf("Error comparing API: %v", err)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("Skipping packages with files that fail to parse: %s\n\n", strings.Join(skipped, ", "))
+	}
+
+	oldVersion, _ := revisionVersion(repo, oldRev)
+	newVersion, _ := revisionVersion(repo, newRev)
+	return printAPIChanges(changes, oldVersion, newVersion, noColor)
+}
+
+// getAPIChanges loads the Go packages at both revisions from the object store
+// and lists every difference of their exported API. Packages with a file
+// that fails to parse at either revision are left out of the comparison, as
+// their API is unknown, and returned sorted.
+func getAPIChanges(repo *git.Repository, oldRev string, newRev string) ([]apiChange, []string, error) {
+	oldAPI, oldUnparsed, err := loadRevisionAPI(repo, oldRev)
+	if err != nil {
+		return nil, nil, err
+	}
+	newAPI, newUnparsed, err := loadRevisionAPI(repo, newRev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unparsed := make(map[string]bool)
+	for dir := range oldUnparsed {
+		unparsed[dir] = true
+	}
+	for dir := range newUnparsed {
+		unparsed[dir] = true
+	}
+	var skipped []string
+	for dir := range unparsed {
+		delete(oldAPI, dir)
+		delete(newAPI, dir)
+		skipped = append(skipped, dir)
+	}
+	sort.Strings(skipped)
+	return compareAPI(oldAPI, newAPI), skipped, nil
+}
+
+func loadRevisionAPI(repo *git.Repository, rev string) (map[string]packageAPI, map[string]bool, error) {
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loadCommitAPI(commit)
+}
+
+// loadCommitAPI parses the non-test Go files of every importable package in
+// the tree of a commit. Main packages and internal, testdata and vendor
+// directories are not part of the public API. It also returns the
+// directories with a file that fails to parse.
+func loadCommitAPI(commit *object.Commit) (map[string]packageAPI, map[string]bool, error) {
+	files, err := commit.Files()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting files of commit %s: %w", commit.Hash, err)
+	}
+
+	apis := make(map[string]packageAPI)
+	unparsed := make(map[string]bool)
+	fset := token.NewFileSet()
+	err = files.ForEach(func(f *object.File) error {
+		if !strings.HasSuffix(f.Name, ".go") || strings.HasSuffix(f.Name, "_test.go") || !isPublicPackageDir(path.Dir(f.Name)) {
+			return nil
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		file, err := parser.ParseFile(fset, f.Name, content, parser.SkipObjectResolution)
+		if err != nil {
+			unparsed[path.Dir(f.Name)] = true
+			return nil
+		}
+		if file.Name.Name == "main" {
+			return nil
+		}
+
+		dir := path.Dir(f.Name)
+		if apis[dir] == nil {
+			apis[dir] = make(packageAPI)
+		}
+		addFileAPI(apis[dir], file)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error loading packages of commit %s: %w", commit.Hash, err)
+	}
+	return apis, unparsed, nil
+}
+
+func isPublicPackageDir(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		if part == "internal" || part == "testdata" || part == "vendor" || (strings.HasPrefix(part, ".") && part != ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// addFileAPI records the exported declarations of a parsed file
+func addFileAPI(api packageAPI, file *ast.File) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				api["func "+d.Name.Name] = funcSignature(d.Type)
+				continue
+			}
+			recv := strings.TrimPrefix(receiverTypeName(d.Recv.List[0].Type), "*")
+			if ast.IsExported(recv) {
+				api["method "+recv+"."+d.Name.Name] = funcSignature(d.Type)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					addTypeAPI(api, s)
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						if !name.IsExported() {
+							continue
+						}
+						typ := ""
+						if s.Type != nil {
+							typ = types.ExprString(s.Type)
+						}
+						api[kind+" "+name.Name] = typ
+					}
+				}
+			}
+		}
+	}
+}
+
+func addTypeAPI(api packageAPI, s *ast.TypeSpec) {
+	if !s.Name.IsExported() {
+		return
+	}
+	name := s.Name.Name
+	typeParams := fieldListString(s.TypeParams, true)
+
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		api["type "+name] = "struct" + typeParams
+		for _, field := range t.Fields.List {
+			typ := types.ExprString(field.Type)
+			if len(field.Names) == 0 {
+				// Embedded fields are named after their type
+				embedded := embeddedFieldName(field.Type)
+				if ast.IsExported(embedded) {
+					api["field "+name+"."+embedded] = typ
+				}
+			}
+			for _, fieldName := range field.Names {
+				if fieldName.IsExported() {
+					api["field "+name+"."+fieldName.Name] = typ
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		api["type "+name] = "interface" + typeParams
+		for _, method := range t.Methods.List {
+			if len(method.Names) == 0 {
+				api["embedded "+name+"."+types.ExprString(method.Type)] = ""
+				continue
+			}
+			for _, methodName := range method.Names {
+				if ft, ok := method.Type.(*ast.FuncType); ok {
+					api["interface method "+name+"."+methodName.Name] = funcSignature(ft)
+				}
+			}
+		}
+	default:
+		assign := ""
+		if s.Assign.IsValid() {
+			assign = "= "
+		}
+		api["type "+name] = assign + types.ExprString(s.Type) + typeParams
+	}
+}
+
+// embeddedFieldName returns the name of an embedded field, the name of its
+// type without package qualifier, pointer or type arguments
+func embeddedFieldName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(t.X)
+	case *ast.IndexExpr:
+		return embeddedFieldName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// funcSignature renders a function type without parameter names, which do
+// not affect compatibility
+func funcSignature(ft *ast.FuncType) string {
+	sig := "func" + fieldListString(ft.TypeParams, true) + "(" + fieldListString(ft.Params, false) + ")"
+	if results := fieldListString(ft.Results, false); results != "" {
+		sig += " (" + results + ")"
+	}
+	return sig
+}
+
+// fieldListString renders the types of a field list. Type parameter lists are
+// rendered with their names since signatures refer to them.
+func fieldListString(fl *ast.FieldList, withNames bool) string {
+	if fl == nil {
+		return ""
+	}
+	var parts []string
+	for _, field := range fl.List {
+		typ := types.ExprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			if withNames && len(field.Names) > 0 {
+				parts = append(parts, field.Names[i].Name+" "+typ)
+			} else {
+				parts = append(parts, typ)
+			}
+		}
+	}
+	if withNames {
+		if len(parts) == 0 {
+			return ""
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// compareAPI classifies the differences between two API snapshots. Removing
+// or changing anything is breaking, and so is adding a method to an existing
+// interface since it breaks every implementation outside the package.
+func compareAPI(oldAPI map[string]packageAPI, newAPI map[string]packageAPI) []apiChange {
+	var changes []apiChange
+	for pkg, oldPkg := range oldAPI {
+		newPkg, ok := newAPI[pkg]
+		if !ok {
+			changes = append(changes, apiChange{Package: pkg, Item: "package", Change: "removed", Breaking: true})
+			continue
+		}
+		for item, oldSig := range oldPkg {
+			newSig, ok := newPkg[item]
+			if !ok {
+				changes = append(changes, apiChange{Package: pkg, Item: item, Change: "removed", Breaking: true})
+			} else if newSig != oldSig {
+				changes = append(changes, apiChange{Package: pkg, Item: item, Change: "changed", Breaking: true})
+			}
+		}
+		for item := range newPkg {
+			if _, ok := oldPkg[item]; ok {
+				continue
+			}
+			breaking := false
+			if strings.HasPrefix(item, "interface method ") || strings.HasPrefix(item, "embedded ") {
+				_, existed := oldPkg["type "+apiItemOwner(item)]
+				breaking = existed
+			}
+			changes = append(changes, apiChange{Package: pkg, Item: item, Change: "added", Breaking: breaking})
+		}
+	}
+	for pkg := range newAPI {
+		if _, ok := oldAPI[pkg]; !ok {
+			changes = append(changes, apiChange{Package: pkg, Item: "package", Change: "added"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Item < changes[j].Item
+	})
+	return changes
+}
+
+// apiItemOwner returns the type an item such as "field Config.Name" belongs to
+func apiItemOwner(item string) string {
+	qualified := item[strings.LastIndex(item, " ")+1:]
+	return strings.SplitN(qualified, ".", 2)[0]
+}
+
+// printAPIChanges prints the API changes and checks them against the version
+// bump between the two revisions, returning false on a breaking change that
+// is not accompanied by a major version bump
+func printAPIChanges(changes []apiChange, oldVersion *semver, newVersion *semver, noColor bool) bool {
+	fmt.Println("API diff:")
+
+	breaking := 0
+	for _, change := range changes {
+		label := "compatible"
+		labelColor := color.New(color.FgGreen)
+		if change.Breaking {
+			label = "breaking"
+			labelColor = color.New(color.FgRed)
+			breaking++
+		}
+		fmt.Printf("%s: %s %s ", change.Package, change.Item, change.Change)
+		if noColor {
+			fmt.Printf("(%s)\n", label)
+		} else {
+			labelColor.Printf("(%s)\n", label)
+		}
+	}
+
+	fmt.Printf("\nBreaking changes: %d\n", breaking)
+	fmt.Printf("Compatible changes: %d\n", len(changes)-breaking)
+
+	if oldVersion == nil || newVersion == nil {
+		fmt.Println("Revisions are not semantic version tags, skipping version check.")
+		return true
+	}
+	if breaking > 0 && !newVersion.allowsBreakingChangesFrom(*oldVersion) {
+		fmt.Printf("Breaking API changes require a major version bump (%s -> %s).\n", oldVersion, newVersion)
+		return false
+	}
+	fmt.Printf("Version bump %s -> %s is consistent with the API changes.\n", oldVersion, newVersion)
+	return true
+}
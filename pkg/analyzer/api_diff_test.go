@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func parseTestAPI(t *testing.T, src string) map[string]packageAPI {
+	file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+	api := make(packageAPI)
+	addFileAPI(api, file)
+	return map[string]packageAPI{"p": api}
+}
+
+func TestCompareAPI(t *testing.T) {
+	oldAPI := parseTestAPI(t, `package p
+
+type Config struct {
+	Name string
+	hidden int
+}
+
+type Store interface {
+	Get(key string) string
+}
+
+func New(name string) *Config { return nil }
+
+func Remove() {}
+
+func (c *Config) Validate() error { return nil }
+`)
+	newAPI := parseTestAPI(t, `package p
+
+type Config struct {
+	Name string
+	Port int
+}
+
+type Store interface {
+	Get(k string) string
+	Put(key, value string)
+}
+
+func New(name string, port int) *Config { return nil }
+
+func (c *Config) Validate() error { return nil }
+
+func Open() {}
+`)
+
+	expected := []apiChange{
+		{Package: "p", Item: "field Config.Port", Change: "added", Breaking: false},
+		{Package: "p", Item: "func New", Change: "changed", Breaking: true},
+		{Package: "p", Item: "func Open", Change: "added", Breaking: false},
+		{Package: "p", Item: "func Remove", Change: "removed", Breaking: true},
+		{Package: "p", Item: "interface method Store.Put", Change: "added", Breaking: true},
+	}
+
+	changes := compareAPI(oldAPI, newAPI)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got %+v, want %+v", changes, expected)
+	}
+}
+
+func TestCompareAPIEmbeddedFields(t *testing.T) {
+	oldAPI := parseTestAPI(t, `package p
+
+import (
+	"io"
+	"sync"
+)
+
+type Stream struct {
+	io.Reader
+	*sync.Mutex
+}
+`)
+	newAPI := parseTestAPI(t, `package p
+
+import "sync"
+
+type Stream struct {
+	*sync.Mutex
+}
+`)
+
+	// Removing an embedded field removes the methods it promotes
+	expected := []apiChange{
+		{Package: "p", Item: "field Stream.Reader", Change: "removed", Breaking: true},
+	}
+
+	changes := compareAPI(oldAPI, newAPI)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got %+v, want %+v", changes, expected)
+	}
+	if _, ok := newAPI["p"]["field Stream.Mutex"]; !ok {
+		t.Errorf("Expected the embedded *sync.Mutex in the API, got %v", newAPI["p"])
+	}
+}
+
+func TestGetAPIChangesSkipsUnparsedPackages(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	author := &object.Signature{Name: "Alice", Email: "alice@example.com"}
+	if _, err := commitFiles(repo, "Add p", author, map[string]string{"p/p.go": "package p\n\nfunc A() {}\n"}); err != nil {
+		t.Fatalf("Failed to create commit: %v", err)
+	}
+	if _, err := commitFiles(repo, "Break p", author, map[string]string{"p/p.go": "package p\n\nfunc A( {}\n"}); err != nil {
+		t.Fatalf("Failed to create commit: %v", err)
+	}
+
+	changes, skipped, err := getAPIChanges(repo, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to compare API: %v", err)
+	}
+	if len(changes) != 0 || !reflect.DeepEqual(skipped, []string{"p"}) {
+		t.Errorf("Expected p to be skipped without changes, got %+v and %v", changes, skipped)
+	}
+}
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *semver
+	}{
+		{"v1.2.3", &semver{Major: 1, Minor: 2, Patch: 3}},
+		{"2.0.0-rc.1+build", &semver{Major: 2, Prerelease: "rc.1"}},
+		{"main", nil},
+		{"v1.2", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, ok := parseSemver(tt.input)
+			if ok != (tt.expected != nil) || !reflect.DeepEqual(v, tt.expected) {
+				t.Errorf("got %v, want %v", v, tt.expected)
+			}
+		})
+	}
+}
+
+func Example_printAPIChanges() {
+	changes := []apiChange{
+		{Package: "p", Item: "func New", Change: "changed", Breaking: true},
+		{Package: "p", Item: "func Open", Change: "added"},
+	}
+
+	ok := printAPIChanges(changes, &semver{Major: 1, Minor: 2}, &semver{Major: 1, Minor: 3}, true)
+	fmt.Println(ok)
+
+	// Output:
+	// API diff:
+	// p: func New changed (breaking)
+	// p: func Open added (compatible)
+	//
+	// Breaking changes: 1
+	// Compatible changes: 1
+	// Breaking API changes require a major version bump (v1.2.0 -> v1.3.0).
+	// false
+}
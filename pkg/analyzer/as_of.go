@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// asOfEnabled reports whether the repository is analyzed at a past point
+func (o Options) asOfEnabled() bool {
+	return o.AsOfRevision != "" || !o.AsOf.IsZero()
+}
+
+// AsOfCommit returns the hash of the commit HEAD is resolved to in the
+// given repository, or an empty string when analyzing its current state.
+// It fails when the as-of revision does not exist or no commit was made
+// before the as-of time.
+func AsOfCommit(repoPath string, opts Options) (string, error) {
+	if !opts.asOfEnabled() {
+		return "", nil
+	}
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("Error opening repository: %w", err)
+	}
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return "", err
+	}
+	return head.Hash.String(), nil
+}
+
+// resolveAsOfRevision returns the commit the as-of revision names
+func resolveAsOfRevision(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("Error resolving %s: %w", revision, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit of %s: %w", revision, err)
+	}
+	return commit, nil
+}
+
+// asOfCutoff returns the time branches are resolved at
+func asOfCutoff(repo *git.Repository, opts Options) (time.Time, error) {
+	if opts.AsOfRevision == "" {
+		return opts.AsOf, nil
+	}
+	commit, err := resolveAsOfRevision(repo, opts.AsOfRevision)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
+// lastCommitBefore follows the first parents of c back to the first commit
+// committed at or before t, the commit the branch pointed to at that time.
+// Reflogs would be more accurate for rewritten branches, but go-git does not
+// read them. It returns nil when the branch had no commits yet.
+func lastCommitBefore(c *object.Commit, t time.Time) (*object.Commit, error) {
+	for c.Committer.When.After(t) {
+		if c.NumParents() == 0 {
+			return nil, nil
+		}
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting first parent: %w", err)
+		}
+		c = parent
+	}
+	return c, nil
+}
+
+// asOfReference returns ref pointing to the commit it pointed to at the
+// as-of point, or nil when the branch had no commits yet
+func asOfReference(repo *git.Repository, ref *plumbing.Reference, opts Options) (*plumbing.Reference, error) {
+	if !opts.asOfEnabled() {
+		return ref, nil
+	}
+	cutoff, err := asOfCutoff(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit of %s: %w", ref.Name().Short(), err)
+	}
+	commit, err = lastCommitBefore(commit, cutoff)
+	if err != nil || commit == nil {
+		return nil, err
+	}
+	return plumbing.NewHashReference(ref.Name(), commit.Hash), nil
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestHeadCommitAsOf(t *testing.T) {
+	repo := createBranches(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		asOfTime time.Time
+		revision string
+		expected string // Message of the expected commit, empty for an error
+	}{
+		{"current", time.Time{}, "", "Merge branch 'done'"},
+		{"date", start.Add(3*time.Minute + 30*time.Second), "", "Small fix"},
+		{"exact date", start.Add(time.Minute), "", "Initial commit"},
+		{"before the first commit", start, "", ""},
+		{"revision", time.Time{}, "feature~1", "Start feature"},
+		{"unknown revision", time.Time{}, "missing", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, err := headCommit(repo, Options{AsOf: tt.asOfTime, AsOfRevision: tt.revision})
+			if tt.expected == "" {
+				if err == nil {
+					t.Errorf("Expected an error, got %s", head.Message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to get HEAD commit: %v", err)
+			}
+			if head.Message != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, head.Message)
+			}
+		})
+	}
+}
+
+func TestBranchesAsOf(t *testing.T) {
+	repo := createBranches(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The revision resolves branches at its commit date, 00:02
+	_, divergence, err := getBranchDivergence(repo, Options{AsOfRevision: "feature~1"})
+	if err != nil {
+		t.Fatalf("Failed to get branch divergence: %v", err)
+	}
+	if len(divergence) != 2 || divergence[0].Name != "feature" || divergence[0].Ahead != 1 || !divergence[1].Merged() {
+		t.Errorf("Expected feature 1 ahead and done merged, got %+v", divergence)
+	}
+
+	// Branches resolve to their last commit at the date
+	feature, err := repo.Reference(plumbing.NewBranchReferenceName("feature"), true)
+	if err != nil {
+		t.Fatalf("Failed to get feature: %v", err)
+	}
+	ref, err := asOfReference(repo, feature, Options{AsOf: start.Add(90 * time.Second)})
+	if err != nil || ref == nil {
+		t.Fatalf("Expected feature to resolve, got %v (%v)", ref, err)
+	}
+	if c, err := repo.CommitObject(ref.Hash()); err != nil || c.Message != "Initial commit" {
+		t.Errorf("Expected feature at the initial commit, got %v (%v)", c, err)
+	}
+
+	// Branches without commits yet do not exist
+	branches, active, inactive, err := getBranchCounts(repo, start, Options{AsOf: start})
+	if err != nil {
+		t.Fatalf("Failed to get branches: %v", err)
+	}
+	if len(branches) != 0 || active != 0 || inactive != 0 {
+		t.Errorf("Expected no branches before the first commit, got %v", branches)
+	}
+}
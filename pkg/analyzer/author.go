@@ -0,0 +1,350 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Longest commit subject that is considered compliant
+const maxSubjectLength = 72
+
+type namedCount struct {
+	Name  string
+	Count int
+}
+
+// ownedArea is a directory where a contributor has more lines blamed on them
+// at HEAD than anyone else
+type ownedArea struct {
+	Directory string
+	Lines     int
+	Total     int
+}
+
+type authorProfile struct {
+	Name           string
+	Email          string
+	Commits        int
+	Merges         int
+	First          time.Time
+	Last           time.Time
+	Months         []string
+	MonthlyCommits []int
+	Added          int
+	Deleted        int
+	CommitSizes    []int // Lines changed per non-merge commit, ascending
+	Directories    []namedCount
+	FileTypes      []namedCount
+	Compliant      int            // Commits with a compliant message
+	MessageIssues  map[string]int // Number of commits per message issue
+	Collaborators  []namedCount   // Other authors by number of shared files
+	OwnedAreas     []ownedArea
+	Codeowners     []string // CODEOWNERS patterns naming the author
+}
+
+// MedianCommitSize returns the typical number of lines changed per commit
+func (p *authorProfile) MedianCommitSize() int {
+	if len(p.CommitSizes) == 0 {
+		return 0
+	}
+	return p.CommitSizes[len(p.CommitSizes)/2]
+}
+
+// AnalyzeAuthor prints the profile of the contributor of the given
+// repository with the given name or email
+func AnalyzeAuthor(repoPath string, author string, depth int, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	authorStats(repo, author, depth, limit, opts)
+}
+
+func authorStats(repo *git.Repository, author string, depth int, limit int, opts Options) {
+	profile, err := getAuthorProfile(repo, author, depth, opts)
+	if err != nil {
+		log.Fatalf("Error getting author profile: %v", err)
+	}
+	printAuthorProfile(profile, limit)
+}
+
+// matchesAuthor reports whether the signature has the given name or email,
+// ignoring case
+func matchesAuthor(sig object.Signature, author string) bool {
+	return strings.EqualFold(sig.Name, author) || strings.EqualFold(sig.Email, author)
+}
+
+// messageIssues returns what keeps a commit message from following the usual
+// git conventions: a short subject line, separated from the body by a blank
+// line
+func messageIssues(message string) []string {
+	var issues []string
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	subject := strings.TrimSpace(lines[0])
+	if subject == "" {
+		issues = append(issues, "empty subject")
+	}
+	if len(subject) > maxSubjectLength {
+		issues = append(issues, fmt.Sprintf("subject longer than %d characters", maxSubjectLength))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		issues = append(issues, "no blank line after subject")
+	}
+	return issues
+}
+
+// getAuthorProfile gathers the activity of the contributor with the given
+// name or email from the history leading up to HEAD, named after their most
+// recent commit. Areas are directories up to depth
+// levels deep where the contributor owns the most lines at HEAD.
+func getAuthorProfile(repo *git.Repository, author string, depth int, opts Options) (*authorProfile, error) {
+	profile := &authorProfile{MessageIssues: make(map[string]int)}
+	directories := make(map[string]int)
+	fileTypes := make(map[string]int)
+	months := make(map[string]int)
+	// Authors of the files by participant key, named after their most recent commit
+	fileAuthors := make(map[string]map[string]bool)
+	authorNames := make(map[string]string)
+	authorFiles := make(map[string]bool)
+
+	var latest time.Time
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		matched := matchesAuthor(c.Author, author)
+		if matched && (profile.Name == "" || c.Author.When.After(latest)) {
+			profile.Name = c.Author.Name
+			profile.Email = c.Author.Email
+			latest = c.Author.When
+		}
+		if c.NumParents() > 1 {
+			if matched {
+				profile.Merges++
+			}
+			return nil
+		}
+		if !matched {
+			files, err := changedFiles(c)
+			if err != nil {
+				return err
+			}
+			key := participantKey(c.Author)
+			if _, ok := authorNames[key]; !ok {
+				authorNames[key] = c.Author.Name
+			}
+			for _, file := range files {
+				if fileAuthors[file] == nil {
+					fileAuthors[file] = make(map[string]bool)
+				}
+				fileAuthors[file][key] = true
+			}
+			return nil
+		}
+
+		// Author dates need not follow the log order
+		if profile.Commits == 0 || c.Author.When.Before(profile.First) {
+			profile.First = c.Author.When
+		}
+		if c.Author.When.After(profile.Last) {
+			profile.Last = c.Author.When
+		}
+		profile.Commits++
+		months[periodKey(c.Author.When.UTC(), PeriodMonth)]++
+		issues := messageIssues(c.Message)
+		if len(issues) == 0 {
+			profile.Compliant++
+		}
+		for _, issue := range issues {
+			profile.MessageIssues[issue]++
+		}
+
+		stats, err := c.Stats()
+		if err != nil {
+			return fmt.Errorf("Error getting stats of commit %s: %w", c.Hash, err)
+		}
+		size := 0
+		for _, stat := range stats {
+			profile.Added += stat.Addition
+			profile.Deleted += stat.Deletion
+			size += stat.Addition + stat.Deletion
+			directories[directoryAtDepth(stat.Name, depth)]++
+			ext := path.Ext(stat.Name)
+			if ext == "" {
+				ext = "(none)"
+			}
+			fileTypes[ext]++
+			authorFiles[stat.Name] = true
+		}
+		profile.CommitSizes = append(profile.CommitSizes, size)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if profile.Name == "" {
+		return nil, fmt.Errorf("No commits found for author %s", author)
+	}
+
+	if profile.Commits > 0 {
+		profile.Months = periodRange(profile.First.UTC(), profile.Last.UTC(), PeriodMonth)
+		for _, month := range profile.Months {
+			profile.MonthlyCommits = append(profile.MonthlyCommits, months[month])
+		}
+	}
+	sort.Ints(profile.CommitSizes)
+	profile.Directories = sortedCounts(directories)
+	profile.FileTypes = sortedCounts(fileTypes)
+
+	collaborators := make(map[string]int)
+	for file := range authorFiles {
+		for key := range fileAuthors[file] {
+			collaborators[authorNames[key]]++
+		}
+	}
+	profile.Collaborators = sortedCounts(collaborators)
+
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	profile.OwnedAreas, err = getOwnedAreas(head, object.Signature{Name: profile.Name, Email: profile.Email}, depth)
+	if err != nil {
+		return nil, err
+	}
+	co, err := loadCodeowners(head)
+	if err != nil {
+		return nil, err
+	}
+	if co != nil {
+		for _, rule := range co.Rules {
+			for _, owner := range rule.Owners {
+				if ownerMatchesAuthor(owner, profile.Name, profile.Email) {
+					profile.Codeowners = append(profile.Codeowners, rule.Pattern)
+					break
+				}
+			}
+		}
+	}
+	return profile, nil
+}
+
+// getOwnedAreas blames every file at commit and returns the directories up to
+// depth levels deep where author has more lines than any other contributor.
+// Lines are matched by email, so that lines written under an older name count.
+func getOwnedAreas(commit *object.Commit, author object.Signature, depth int) ([]ownedArea, error) {
+	lines := make(map[string]map[string]int)
+	files, err := commit.Files()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting files of commit %s: %w", commit.Hash, err)
+	}
+	err = files.ForEach(func(f *object.File) error {
+		if binary, err := f.IsBinary(); err != nil || binary {
+			return err
+		}
+		result, err := git.Blame(commit, f.Name)
+		if err != nil {
+			return fmt.Errorf("Error blaming %s: %w", f.Name, err)
+		}
+		dir := directoryAtDepth(f.Name, depth)
+		if lines[dir] == nil {
+			lines[dir] = make(map[string]int)
+		}
+		for _, line := range result.Lines {
+			lines[dir][participantKey(object.Signature{Name: line.AuthorName, Email: line.Author})]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := participantKey(author)
+	var owned []ownedArea
+	for dir, byAuthor := range lines {
+		area := ownedArea{Directory: dir, Lines: byAuthor[key]}
+		top := true
+		for other, n := range byAuthor {
+			area.Total += n
+			if other != key && n >= area.Lines {
+				top = false
+			}
+		}
+		if top && area.Lines > 0 {
+			owned = append(owned, area)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		if owned[i].Lines != owned[j].Lines {
+			return owned[i].Lines > owned[j].Lines
+		}
+		return owned[i].Directory < owned[j].Directory
+	})
+	return owned, nil
+}
+
+func sortedCounts(counts map[string]int) []namedCount {
+	var sorted []namedCount
+	for name, count := range counts {
+		sorted = append(sorted, namedCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func printNamedCounts(title string, counts []namedCount, unit string, limit int) {
+	fmt.Printf("\n%s:\n", title)
+	if len(counts) == 0 {
+		fmt.Println("None")
+	}
+	for i, c := range counts {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: %d %s\n", c.Name, c.Count, unit)
+	}
+}
+
+func printAuthorProfile(p *authorProfile, limit int) {
+	fmt.Printf("Profile of %s <%s>:\n", p.Name, p.Email)
+	fmt.Printf("\nCommits: %d (and %d merges)\n", p.Commits, p.Merges)
+	if p.Commits > 0 {
+		fmt.Printf("Active from %s to %s\n", p.First.Format("2006-01-02"), p.Last.Format("2006-01-02"))
+		fmt.Printf("Commits per month from %s to %s: %s\n", p.Months[0], p.Months[len(p.Months)-1], sparkline(p.MonthlyCommits))
+		fmt.Printf("Lines added: %d, lines deleted: %d\n", p.Added, p.Deleted)
+		fmt.Printf("Typical commit size: %d lines changed\n", p.MedianCommitSize())
+		fmt.Printf("Compliant commit messages: %d of %d (%.1f%%)\n", p.Compliant, p.Commits, float64(p.Compliant)/float64(p.Commits)*100)
+		issues := sortedCounts(p.MessageIssues)
+		for _, issue := range issues {
+			fmt.Printf("  %s: %d commits\n", issue.Name, issue.Count)
+		}
+	}
+
+	printNamedCounts("Top directories", p.Directories, "file changes", limit)
+	printNamedCounts("Top file types", p.FileTypes, "file changes", limit)
+	printNamedCounts("Frequent collaborators", p.Collaborators, "shared files", limit)
+
+	fmt.Println("\nOwned areas:")
+	if len(p.OwnedAreas) == 0 && len(p.Codeowners) == 0 {
+		fmt.Println("None")
+	}
+	for i, area := range p.OwnedAreas {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: %d of %d lines (%.1f%%)\n", area.Directory, area.Lines, area.Total, float64(area.Lines)/float64(area.Total)*100)
+	}
+	for _, pattern := range p.Codeowners {
+		fmt.Printf("%s: listed in CODEOWNERS\n", pattern)
+	}
+}
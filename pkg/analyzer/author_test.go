@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetAuthorProfile(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: start.AddDate(0, 1, 0)}
+	// Alice changed their display name since the first commit
+	alice2 := &object.Signature{Name: "Alice Smith", Email: "alice@example.com", When: start.AddDate(0, 2, 0)}
+
+	if _, err := commitFiles(repo, "Add sources", alice, map[string]string{"src/a.go": "1\n2\n3\n", "docs/a.md": "a\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "Update sources", bob, map[string]string{"src/a.go": "1\n2\nb\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "Update docs\nwithout a blank line", alice2, map[string]string{"docs/a.md": "a\nb\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+
+	profile, err := getAuthorProfile(repo, "ALICE@example.com", 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get author profile: %v", err)
+	}
+
+	if profile.Name != "Alice Smith" || profile.Commits != 2 || profile.Added != 5 || profile.Deleted != 0 {
+		t.Errorf("Expected 2 commits by Alice adding 5 lines, got %+v", profile)
+	}
+	if !reflect.DeepEqual(profile.MonthlyCommits, []int{1, 0, 1}) {
+		t.Errorf("Expected commits in January and March, got %v", profile.MonthlyCommits)
+	}
+	if profile.Compliant != 1 || profile.MessageIssues["no blank line after subject"] != 1 {
+		t.Errorf("Expected 1 compliant message, got %d and %v", profile.Compliant, profile.MessageIssues)
+	}
+	expected := []namedCount{{Name: "docs", Count: 2}, {Name: "src", Count: 1}}
+	if !reflect.DeepEqual(profile.Directories, expected) {
+		t.Errorf("got %+v, want %+v", profile.Directories, expected)
+	}
+	if !reflect.DeepEqual(profile.Collaborators, []namedCount{{Name: "Bob", Count: 1}}) {
+		t.Errorf("Expected Bob as collaborator, got %+v", profile.Collaborators)
+	}
+	if !reflect.DeepEqual(profile.OwnedAreas, []ownedArea{{Directory: "docs", Lines: 2, Total: 2}, {Directory: "src", Lines: 2, Total: 3}}) {
+		t.Errorf("Expected Alice to own src and docs, got %+v", profile.OwnedAreas)
+	}
+
+	if _, err := getAuthorProfile(repo, "carol", 1, Options{}); err == nil {
+		t.Error("Expected an error for an unknown author")
+	}
+}
+
+func TestGetAuthorProfileDatesAndMerges(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// The second commit keeps an older author date, e.g. after a rebase
+	start := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start}
+	backdated := &object.Signature{Name: "Alice Smith", Email: "alice@example.com", When: start.AddDate(0, -2, 0)}
+	carol := &object.Signature{Name: "Carol", Email: "carol@example.com", When: start.AddDate(0, 0, 1)}
+
+	first, err := commitFiles(repo, "Add a", alice, map[string]string{"a.txt": "a\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	second, err := commitFiles(repo, "Add b", backdated, map[string]string{"b.txt": "b\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	_, err = wt.Commit("Merge branch 'b'", &git.CommitOptions{Author: carol, Parents: []plumbing.Hash{second, first}, AllowEmptyCommits: true})
+	if err != nil {
+		t.Fatalf("Failed to create merge commit: %v", err)
+	}
+
+	profile, err := getAuthorProfile(repo, "alice@example.com", 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get author profile: %v", err)
+	}
+	if !profile.First.Equal(backdated.When) || !profile.Last.Equal(alice.When) {
+		t.Errorf("Expected activity from %v to %v, got %v to %v", backdated.When, alice.When, profile.First, profile.Last)
+	}
+	if profile.Name != "Alice" || !reflect.DeepEqual(profile.MonthlyCommits, []int{1, 0, 1}) {
+		t.Errorf("Expected Alice with commits in January and March, got %s and %v", profile.Name, profile.MonthlyCommits)
+	}
+
+	profile, err = getAuthorProfile(repo, "carol", 1, Options{})
+	if err != nil {
+		t.Fatalf("Expected a profile for an author with only merges: %v", err)
+	}
+	if profile.Commits != 0 || profile.Merges != 1 {
+		t.Errorf("Expected 1 merge and no commits, got %d and %d", profile.Merges, profile.Commits)
+	}
+}
+
+func TestMessageIssues(t *testing.T) {
+	tests := map[string][]string{
+		"Fix parser\n\nDetails\n": nil,
+		"Fix parser\nDetails\n":   {"no blank line after subject"},
+		"\n":                      {"empty subject"},
+		strings.Repeat("x", 73):   {"subject longer than 72 characters"},
+		strings.Repeat("x", 72):   nil,
+	}
+	for message, expected := range tests {
+		if got := messageIssues(message); !reflect.DeepEqual(got, expected) {
+			t.Errorf("messageIssues(%q) = %v, want %v", message, got, expected)
+		}
+	}
+}
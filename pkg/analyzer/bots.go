@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Emails of well known bots that do not follow a recognizable naming pattern
+var botEmails = map[string]bool{
+	"bot@renovateapp.com":               true,
+	"support@dependabot.com":            true,
+	"semantic-release-bot@martynus.net": true,
+	"action@github.com":                 true,
+	"noreply@weblate.org":               true,
+}
+
+// Patterns matched against author names and the local part of their emails
+var defaultBotPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\[bot\]$`),
+	regexp.MustCompile(`(?i)[-_. ]bot$`),
+	regexp.MustCompile(`(?i)^(dependabot|renovate|greenkeeper|snyk-bot|github-actions|mergify|codecov)`),
+}
+
+// BotMatcher recognizes bot accounts by author name or email
+type BotMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+// NewBotMatcher returns a matcher for well known bots and the accounts
+// matching any of the given regular expressions
+func NewBotMatcher(patterns []string) (*BotMatcher, error) {
+	m := &BotMatcher{patterns: append([]*regexp.Regexp{}, defaultBotPatterns...)}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot pattern %s: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// IsBot reports whether the author with the given name and email is a bot
+// account
+func (m *BotMatcher) IsBot(name string, email string) bool {
+	email = strings.ToLower(email)
+	if botEmails[email] {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	for _, re := range m.patterns {
+		if re.MatchString(name) || re.MatchString(local) || re.MatchString(email) {
+			return true
+		}
+	}
+	return false
+}
+
+type botActivity struct {
+	Name    string
+	Commits int
+	Added   int
+	Deleted int
+}
+
+// AnalyzeBotActivity prints how much of the activity in the given repository
+// comes from the accounts bots recognizes
+func AnalyzeBotActivity(repoPath string, bots *BotMatcher, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	botActivityStats(repo, bots, opts)
+}
+
+func botActivityStats(repo *git.Repository, matcher *BotMatcher, opts Options) {
+	bots, commitCount, err := getBotActivity(repo, matcher, opts)
+	if err != nil {
+		log.Fatalf("Error getting bot activity: %v", err)
+	}
+	printBotActivity(bots, commitCount)
+}
+
+// getBotActivity counts the commits and changed lines of every account
+// matcher recognizes as a bot, along with the total number of commits
+func getBotActivity(repo *git.Repository, matcher *BotMatcher, opts Options) ([]botActivity, int, error) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash})
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error getting commit log: %w", err)
+	}
+
+	bots := make(map[string]*botActivity)
+	commitCount := 0
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		commitCount++
+		if !matcher.IsBot(c.Author.Name, c.Author.Email) {
+			return nil
+		}
+		bot, ok := bots[c.Author.Name]
+		if !ok {
+			bot = &botActivity{Name: c.Author.Name}
+			bots[c.Author.Name] = bot
+		}
+		bot.Commits++
+		if c.NumParents() > 1 {
+			return nil
+		}
+		stats, err := c.Stats()
+		if err != nil {
+			return fmt.Errorf("Error getting stats of commit %s: %w", c.Hash, err)
+		}
+		for _, stat := range stats {
+			bot.Added += stat.Addition
+			bot.Deleted += stat.Deletion
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+
+	var sorted []botActivity
+	for _, bot := range bots {
+		sorted = append(sorted, *bot)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Commits != sorted[j].Commits {
+			return sorted[i].Commits > sorted[j].Commits
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, commitCount, nil
+}
+
+func printBotActivity(bots []botActivity, commitCount int) {
+	fmt.Println("\nAutomated activity:")
+	botCommits := 0
+	for _, bot := range bots {
+		botCommits += bot.Commits
+	}
+	share := 0.0
+	if commitCount > 0 {
+		share = float64(botCommits) / float64(commitCount) * 100
+	}
+	fmt.Printf("Bot commits: %d of %d (%.1f%%)\n", botCommits, commitCount, share)
+	for _, bot := range bots {
+		fmt.Printf("%s: %d commits, +%d/-%d lines\n", bot.Name, bot.Commits, bot.Added, bot.Deleted)
+	}
+}
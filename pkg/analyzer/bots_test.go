@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestIsBot(t *testing.T) {
+	tests := []struct {
+		name, email string
+		expected    bool
+	}{
+		{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", true},
+		{"Renovate Bot", "bot@renovateapp.com", true},
+		{"github-actions", "41898282+github-actions[bot]@users.noreply.github.com", true},
+		{"Release", "release-bot@example.com", true},
+		{"Alice", "alice@example.com", false},
+		{"Abbot", "abbot@example.com", false},
+	}
+	bots, err := NewBotMatcher([]string{`^release-`})
+	if err != nil {
+		t.Fatalf("Failed to create bot matcher: %v", err)
+	}
+	for _, test := range tests {
+		if got := bots.IsBot(test.name, test.email); got != test.expected {
+			t.Errorf("IsBot(%q, %q) = %v, want %v", test.name, test.email, got, test.expected)
+		}
+	}
+	if bots.IsBot("Release Manager", "release-manager@example.com") != true {
+		t.Error("Expected the added pattern to match")
+	}
+	if defaults, _ := NewBotMatcher(nil); defaults.IsBot("Release Manager", "release-manager@example.com") {
+		t.Error("Expected added patterns to stay with their matcher")
+	}
+	if _, err := NewBotMatcher([]string{"("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestExcludeBots(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	authors := []*object.Signature{
+		{Name: "Alice", Email: "alice@example.com"},
+		{Name: "dependabot[bot]", Email: "49699333+dependabot[bot]@users.noreply.github.com"},
+		{Name: "dependabot[bot]", Email: "49699333+dependabot[bot]@users.noreply.github.com"},
+	}
+	for i, author := range authors {
+		author.When = time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)
+		if _, err := commitFiles(repo, "update", author, map[string]string{"go.sum": fmt.Sprintf("%d\n", i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	bots, err := NewBotMatcher(nil)
+	if err != nil {
+		t.Fatalf("Failed to create bot matcher: %v", err)
+	}
+	opts := Options{Bots: bots}
+
+	commitCounts, commitCount, err := getCommitCounts(repo, opts)
+	if err != nil {
+		t.Fatalf("Failed to count commits: %v", err)
+	}
+	if commitCount != 1 || !reflect.DeepEqual(commitCounts, map[string]int{"Alice": 1}) {
+		t.Errorf("Expected only Alice's commit, got %d commits and %v", commitCount, commitCounts)
+	}
+	head, err := headCommit(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get HEAD commit: %v", err)
+	}
+	churn, err := getFileChurn(repo, head, opts)
+	if err != nil {
+		t.Fatalf("Failed to get file churn: %v", err)
+	}
+	if churn["go.sum"] != 1 {
+		t.Errorf("Expected 1 change to go.sum, got %v", churn)
+	}
+
+	activity, commitCount, err := getBotActivity(repo, bots, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get bot activity: %v", err)
+	}
+	expected := []botActivity{{Name: "dependabot[bot]", Commits: 2, Added: 2, Deleted: 2}}
+	if commitCount != 3 || !reflect.DeepEqual(activity, expected) {
+		t.Errorf("got %d commits and %+v, want 3 and %+v", commitCount, activity, expected)
+	}
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Default pattern of ticket IDs, such as ABC-123
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// Long-lived branches the naming policy does not apply to
+var longLivedBranches = []string{"main", "master", "develop"}
+
+// BranchNamePolicy holds the rules branch names are checked against
+type BranchNamePolicy struct {
+	Pattern   *regexp.Regexp // Names must match, nil for any
+	Prefixes  []string       // Names must start with one of them, empty for any
+	MaxLength int            // Zero for no limit
+	Ticket    *regexp.Regexp // Names must contain a ticket ID, nil when not required
+}
+
+// NewBranchNamePolicy returns the branch naming policy with the given rules.
+// An empty pattern or prefix list and a zero maximum length disable the
+// respective rule, and ticket IDs are only required with a non-empty ticket
+// pattern. Without any rule, it returns nil and branch names are not checked.
+func NewBranchNamePolicy(pattern string, prefixes []string, maxLength int, ticketPattern string) (*BranchNamePolicy, error) {
+	if pattern == "" && len(prefixes) == 0 && maxLength == 0 && ticketPattern == "" {
+		return nil, nil
+	}
+	policy := &BranchNamePolicy{Prefixes: prefixes, MaxLength: maxLength}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+		policy.Pattern = re
+	}
+	if ticketPattern != "" {
+		re, err := regexp.Compile(ticketPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ticket pattern %q: %w", ticketPattern, err)
+		}
+		policy.Ticket = re
+	}
+	return policy, nil
+}
+
+// violations returns the rules of the policy a branch name breaks
+func (p *BranchNamePolicy) violations(name string) []string {
+	var broken []string
+	if p.Pattern != nil && !p.Pattern.MatchString(name) {
+		broken = append(broken, fmt.Sprintf("does not match %s", p.Pattern))
+	}
+	if len(p.Prefixes) > 0 {
+		allowed := false
+		for _, prefix := range p.Prefixes {
+			if strings.HasPrefix(name, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			broken = append(broken, fmt.Sprintf("prefix is not one of %s", strings.Join(p.Prefixes, ", ")))
+		}
+	}
+	if p.MaxLength > 0 && len(name) > p.MaxLength {
+		broken = append(broken, fmt.Sprintf("longer than %d characters", p.MaxLength))
+	}
+	if p.Ticket != nil && !p.Ticket.MatchString(name) {
+		broken = append(broken, "no ticket ID")
+	}
+	return broken
+}
+
+type branchNameViolation struct {
+	Name       string
+	Author     string // Author of the last commit
+	LastCommit time.Time
+	Violations []string
+}
+
+// getBranchNameViolations checks every local branch except the default and
+// long-lived branches against the policy, sorted by name
+func getBranchNameViolations(repo *git.Repository, policy *BranchNamePolicy, opts Options) ([]branchNameViolation, error) {
+	base, err := defaultBranch(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting branches: %w", err)
+	}
+	var offending []branchNameViolation
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if ref.Name() == base.Name() || isProtected(name, longLivedBranches) {
+			return nil
+		}
+		ref, err := asOfReference(repo, ref, opts)
+		if err != nil || ref == nil {
+			return err
+		}
+		broken := policy.violations(name)
+		if len(broken) == 0 {
+			return nil
+		}
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("Error getting commit of %s: %w", name, err)
+		}
+		offending = append(offending, branchNameViolation{
+			Name:       name,
+			Author:     commit.Author.Name,
+			LastCommit: commit.Committer.When,
+			Violations: broken,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(offending, func(i, j int) bool {
+		return offending[i].Name < offending[j].Name
+	})
+	return offending, nil
+}
+
+func printBranchNameViolations(offending []branchNameViolation, now time.Time) {
+	if len(offending) == 0 {
+		fmt.Println("No branch naming violations detected.")
+		return
+	}
+	fmt.Printf("Detected %d branch(es) violating the naming policy:\n", len(offending))
+	for _, b := range offending {
+		fmt.Printf("  %s (%s, last commit %d days ago): %s\n",
+			b.Name, b.Author, days(now.Sub(b.LastCommit)), strings.Join(b.Violations, "; "))
+	}
+}
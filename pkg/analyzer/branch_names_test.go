@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestBranchNameViolations(t *testing.T) {
+	policy := &BranchNamePolicy{
+		Pattern:   regexp.MustCompile(`^[a-z0-9/-]+$`),
+		Prefixes:  []string{"feature/", "fix/"},
+		MaxLength: 20,
+		Ticket:    regexp.MustCompile(`[a-z]+-[0-9]+`),
+	}
+
+	tests := []struct {
+		name     string
+		branch   string
+		expected []string
+	}{
+		{"compliant", "feature/abc-12-login", nil},
+		{"no prefix", "abc-12-login", []string{"prefix is not one of feature/, fix/"}},
+		{"too long", "fix/abc-12-broken-login-form", []string{"longer than 20 characters"}},
+		{"no ticket", "fix/login", []string{"no ticket ID"}},
+		{"pattern", "fix/ABC-12", []string{"does not match ^[a-z0-9/-]+$", "no ticket ID"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.violations(tt.branch); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetBranchNameViolations(t *testing.T) {
+	repo := createBranches(t)
+
+	offending, err := getBranchNameViolations(repo, &BranchNamePolicy{Prefixes: []string{"feature"}}, Options{})
+	if err != nil {
+		t.Fatalf("Failed to check branch names: %v", err)
+	}
+	if len(offending) != 1 || offending[0].Name != "done" || offending[0].Author != "Alice" {
+		t.Errorf("Expected only done to violate the policy, got %+v", offending)
+	}
+}
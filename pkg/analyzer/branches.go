@@ -0,0 +1,401 @@
+package analyzer
+
+import (
+	"container/heap"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+type branchDivergence struct {
+	Name           string
+	Ahead          int       // Commits not in the default branch
+	Behind         int       // Commits of the default branch missing from the branch
+	MergeBase      time.Time // Committer date of the merge base, zero for unrelated histories
+	OldestUnmerged time.Time // Author date of the oldest commit not in the default branch
+	LastAuthor     string
+	LastCommit     time.Time
+}
+
+// Merged reports whether every commit of the branch is in the default branch
+func (b branchDivergence) Merged() bool {
+	return b.Ahead == 0
+}
+
+// defaultBranch returns the branch other branches are compared against: the
+// branch origin/HEAD points to, otherwise main or master, otherwise the
+// checked out branch
+func defaultBranch(repo *git.Repository) (*plumbing.Reference, error) {
+	if remoteHead, err := repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), false); err == nil && remoteHead.Type() == plumbing.SymbolicReference {
+		name := strings.TrimPrefix(remoteHead.Target().Short(), "origin/")
+		if ref, err := repo.Reference(plumbing.NewBranchReferenceName(name), true); err == nil {
+			return ref, nil
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if ref, err := repo.Reference(plumbing.NewBranchReferenceName(name), true); err == nil {
+			return ref, nil
+		}
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting HEAD reference: %w", err)
+	}
+	return ref, nil
+}
+
+// ancestors returns the hashes of all commits reachable from hash
+func ancestors(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]*object.Commit, error) {
+	commitIter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	commits := make(map[plumbing.Hash]*object.Commit)
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = c
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+	return commits, nil
+}
+
+// generations numbers the given commits so that every commit comes after its
+// parents: 1 for root commits, otherwise one more than its highest parent
+func generations(commits map[plumbing.Hash]*object.Commit) map[plumbing.Hash]int {
+	gen := make(map[plumbing.Hash]int, len(commits))
+	for hash := range commits {
+		stack := []plumbing.Hash{hash}
+		for len(stack) > 0 {
+			h := stack[len(stack)-1]
+			if gen[h] > 0 {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			g, pending := 1, false
+			for _, p := range commits[h].ParentHashes {
+				if _, ok := commits[p]; !ok {
+					continue
+				}
+				if gen[p] == 0 {
+					stack = append(stack, p)
+					pending = true
+				} else if gen[p] >= g {
+					g = gen[p] + 1
+				}
+			}
+			if !pending {
+				gen[h] = g
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return gen
+}
+
+// generationQueue pops the commit with the highest generation first, so a
+// commit is only reached once all of its queued descendants are done
+type generationQueue struct {
+	hashes []plumbing.Hash
+	gen    map[plumbing.Hash]int
+}
+
+func (q *generationQueue) Len() int           { return len(q.hashes) }
+func (q *generationQueue) Less(i, j int) bool { return q.gen[q.hashes[i]] > q.gen[q.hashes[j]] }
+func (q *generationQueue) Swap(i, j int)      { q.hashes[i], q.hashes[j] = q.hashes[j], q.hashes[i] }
+func (q *generationQueue) Push(x any)         { q.hashes = append(q.hashes, x.(plumbing.Hash)) }
+func (q *generationQueue) Pop() any {
+	h := q.hashes[len(q.hashes)-1]
+	q.hashes = q.hashes[:len(q.hashes)-1]
+	return h
+}
+
+// countBehind counts the ancestors of base, given as commits with their
+// generations, that are not ancestors of any of the commits in reached. It
+// walks down from base and reached together and stops once every commit left
+// to visit is an ancestor of reached.
+func countBehind(base plumbing.Hash, reached []plumbing.Hash, commits map[plumbing.Hash]*object.Commit, gen map[plumbing.Hash]int) int {
+	const fromBase, fromBranch = 1, 2
+	paint := make(map[plumbing.Hash]int)
+	queue := &generationQueue{gen: gen}
+	baseOnly := 0
+	visit := func(h plumbing.Hash, flags int) {
+		old := paint[h]
+		paint[h] |= flags
+		switch {
+		case old == 0:
+			heap.Push(queue, h)
+			if paint[h] == fromBase {
+				baseOnly++
+			}
+		case old == fromBase && paint[h] != fromBase:
+			baseOnly--
+		}
+	}
+	for _, h := range reached {
+		visit(h, fromBranch)
+	}
+	visit(base, fromBase)
+
+	behind := 0
+	for baseOnly > 0 {
+		h := heap.Pop(queue).(plumbing.Hash)
+		flags := paint[h]
+		if flags == fromBase {
+			baseOnly--
+			behind++
+		}
+		for _, p := range commits[h].ParentHashes {
+			if _, ok := commits[p]; ok {
+				visit(p, flags)
+			}
+		}
+	}
+	return behind
+}
+
+// getBranchDivergence compares every local branch except the default branch
+// with the default branch, listing unmerged branches with the oldest
+// unmerged work first and merged branches last. The history of the default
+// branch is loaded once; each branch is only walked down to where it joins
+// that history.
+func getBranchDivergence(repo *git.Repository, opts Options) (string, []branchDivergence, error) {
+	base, err := defaultBranch(repo)
+	if err != nil {
+		return "", nil, err
+	}
+	name := base.Name().Short()
+	base, err = asOfReference(repo, base, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	if base == nil {
+		return "", nil, fmt.Errorf("No commits on %s at the as-of point", name)
+	}
+	baseCommits, err := ancestors(repo, base.Hash())
+	if err != nil {
+		return "", nil, err
+	}
+	baseCommit := baseCommits[base.Hash()]
+	gen := generations(baseCommits)
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return "", nil, fmt.Errorf("Error getting branches: %w", err)
+	}
+	var divergence []branchDivergence
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name() == base.Name() {
+			return nil
+		}
+		ref, err := asOfReference(repo, ref, opts)
+		if err != nil || ref == nil {
+			return err
+		}
+		tip, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("Error getting commit of %s: %w", ref.Name().Short(), err)
+		}
+		d := branchDivergence{Name: ref.Name().Short(), LastAuthor: tip.Author.Name, LastCommit: tip.Committer.When}
+
+		// Walk the branch down to the commits where it joins the default branch
+		var reached []plumbing.Hash
+		seen := map[plumbing.Hash]bool{tip.Hash: true}
+		stack := []plumbing.Hash{tip.Hash}
+		for len(stack) > 0 {
+			h := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if _, ok := baseCommits[h]; ok {
+				reached = append(reached, h)
+				continue
+			}
+			c, err := repo.CommitObject(h)
+			if err != nil {
+				return fmt.Errorf("Error getting commit object: %w", err)
+			}
+			d.Ahead++
+			if d.OldestUnmerged.IsZero() || c.Author.When.Before(d.OldestUnmerged) {
+				d.OldestUnmerged = c.Author.When
+			}
+			for _, p := range c.ParentHashes {
+				if !seen[p] {
+					seen[p] = true
+					stack = append(stack, p)
+				}
+			}
+		}
+		d.Behind = countBehind(base.Hash(), reached, baseCommits, gen)
+
+		// With several merge bases, as after criss-cross merges, the newest
+		// one is where the branch last caught up
+		if len(reached) > 0 {
+			mergeBase, err := newestMergeBase(tip, baseCommit)
+			if err != nil {
+				return err
+			}
+			if mergeBase != nil {
+				d.MergeBase = mergeBase.Committer.When
+			}
+		}
+		divergence = append(divergence, d)
+		return nil
+	})
+	if err != nil && err != storer.ErrStop {
+		return "", nil, err
+	}
+
+	sort.Slice(divergence, func(i, j int) bool {
+		a, b := divergence[i], divergence[j]
+		if a.Merged() != b.Merged() {
+			return !a.Merged()
+		}
+		if !a.OldestUnmerged.Equal(b.OldestUnmerged) {
+			return a.OldestUnmerged.Before(b.OldestUnmerged)
+		}
+		return a.Name < b.Name
+	})
+	return base.Name().Short(), divergence, nil
+}
+
+func printBranchDivergence(base string, divergence []branchDivergence, now time.Time) {
+	fmt.Printf("\nDivergence from %s:\n", base)
+	if len(divergence) == 0 {
+		fmt.Println("No other branches.")
+		return
+	}
+	for _, d := range divergence {
+		status := "unmerged"
+		if d.Merged() {
+			status = "merged"
+		}
+		mergeBase := "no merge base"
+		if !d.MergeBase.IsZero() {
+			mergeBase = "merge base " + d.MergeBase.Format("2006-01-02")
+		}
+		fmt.Printf("%s: %s, %d ahead, %d behind, %s", d.Name, status, d.Ahead, d.Behind, mergeBase)
+		if !d.Merged() {
+			fmt.Printf(", oldest unmerged commit %d days old", days(now.Sub(d.OldestUnmerged)))
+		}
+		fmt.Printf(", last commit by %s on %s\n", d.LastAuthor, d.LastCommit.Format("2006-01-02"))
+	}
+}
+
+// Default patterns of branches that are never pruned
+var DefaultProtectedBranches = []string{"main", "master", "develop", "release/*"}
+
+type pruneCandidate struct {
+	Name       string
+	Reason     string
+	LastCommit time.Time
+}
+
+// AnalyzePruneBranches lists the local branches of the given repository that
+// are fully merged into the default branch or inactive for longer than
+// inactiveDays days. With apply, their refs are deleted unless dryRun is set.
+// Branches matching DefaultProtectedBranches or one of the protected patterns
+// are never listed.
+func AnalyzePruneBranches(repoPath string, inactiveDays int, protected []string, apply bool, dryRun bool, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	protected = append(append([]string{}, DefaultProtectedBranches...), protected...)
+	pruneBranches(repo, time.Duration(inactiveDays)*24*time.Hour, protected, apply, dryRun, opts)
+}
+
+func pruneBranches(repo *git.Repository, inactive time.Duration, protected []string, apply bool, dryRun bool, opts Options) {
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+	candidates, err := getPruneCandidates(repo, inactive, protected, now, opts)
+	if err != nil {
+		log.Fatalf("Error getting stale branches: %v", err)
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No stale branches found.")
+		return
+	}
+
+	for _, c := range candidates {
+		switch {
+		case dryRun:
+			fmt.Printf("Would delete branch %s (%s)\n", c.Name, c.Reason)
+		case apply:
+			if err := deleteBranch(repo, c.Name); err != nil {
+				log.Fatalf("Error deleting branch %s: %v", c.Name, err)
+			}
+			fmt.Printf("Deleted branch %s (%s)\n", c.Name, c.Reason)
+		default:
+			fmt.Printf("%s: %s, last commit on %s\n", c.Name, c.Reason, c.LastCommit.Format("2006-01-02"))
+		}
+	}
+	if !apply && !dryRun {
+		fmt.Printf("\n%d stale branches, run with --apply to delete them\n", len(candidates))
+	}
+}
+
+// isProtected reports whether a branch name matches one of the patterns
+func isProtected(name string, protected []string) bool {
+	for _, pattern := range protected {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// getPruneCandidates returns the local branches, other than the default and
+// the checked out branch, that are fully merged into the default branch or
+// whose last commit is older than inactive, sorted by name
+func getPruneCandidates(repo *git.Repository, inactive time.Duration, protected []string, now time.Time, opts Options) ([]pruneCandidate, error) {
+	_, divergence, err := getBranchDivergence(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	var current string
+	if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+		current = head.Name().Short()
+	}
+
+	var candidates []pruneCandidate
+	for _, d := range divergence {
+		if d.Name == current || isProtected(d.Name, protected) {
+			continue
+		}
+		c := pruneCandidate{Name: d.Name, LastCommit: d.LastCommit}
+		if d.Merged() {
+			c.Reason = "merged"
+		} else if age := now.Sub(d.LastCommit); age >= inactive {
+			c.Reason = fmt.Sprintf("inactive for %d days", days(age))
+		} else {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates, nil
+}
+
+// deleteBranch removes the ref of a local branch and its configuration
+func deleteBranch(repo *git.Repository, name string) error {
+	if err := repo.Storer.RemoveReference(plumbing.NewBranchReferenceName(name)); err != nil {
+		return err
+	}
+	if err := repo.DeleteBranch(name); err != nil && err != git.ErrBranchNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// createBranches creates master with three commits, a feature branch with two
+// unmerged commits off the first one and a done branch merged into master
+func createBranches(t *testing.T) *git.Repository {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	a := storeCommit(t, repo, 1, "Initial commit")
+	b1 := storeCommit(t, repo, 2, "Start feature", a)
+	done := storeCommit(t, repo, 3, "Small fix", a)
+	b2 := storeCommit(t, repo, 4, "Continue feature", b1)
+	c := storeCommit(t, repo, 5, "Merge branch 'done'", done, a)
+
+	refs := map[string]plumbing.Hash{"master": c, "feature": b2, "done": done}
+	for name, hash := range refs {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), hash)); err != nil {
+			t.Fatalf("Failed to set %s: %v", name, err)
+		}
+	}
+	return repo
+}
+
+func TestGetBranchDivergence(t *testing.T) {
+	repo := createBranches(t)
+
+	base, divergence, err := getBranchDivergence(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get branch divergence: %v", err)
+	}
+	if base != "master" || len(divergence) != 2 {
+		t.Fatalf("Expected 2 branches compared with master, got %s and %+v", base, divergence)
+	}
+
+	feature := divergence[0]
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if feature.Name != "feature" || feature.Ahead != 2 || feature.Behind != 2 || feature.Merged() {
+		t.Errorf("Expected feature 2 ahead and 2 behind, got %+v", feature)
+	}
+	if !feature.MergeBase.Equal(start.Add(time.Minute)) || !feature.OldestUnmerged.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("Expected the initial commit as merge base and the first feature commit as oldest, got %+v", feature)
+	}
+	if done := divergence[1]; done.Name != "done" || !done.Merged() || done.Behind != 1 {
+		t.Errorf("Expected done merged and 1 behind, got %+v", done)
+	}
+}
+
+func TestGetBranchDivergenceCrissCross(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// master and topic merge each other, leaving two merge bases
+	a := storeCommit(t, repo, 1, "Initial commit")
+	g := storeCommit(t, repo, 2, "Master work", a)
+	h := storeCommit(t, repo, 3, "Topic work", a)
+	m1 := storeCommit(t, repo, 4, "Merge branch 'topic'", g, h)
+	m2 := storeCommit(t, repo, 5, "Merge branch 'master' into topic", h, g)
+	x := storeCommit(t, repo, 6, "More master work", m1)
+	y := storeCommit(t, repo, 7, "More topic work", m2)
+
+	refs := map[string]plumbing.Hash{"master": x, "topic": y}
+	for name, hash := range refs {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), hash)); err != nil {
+			t.Fatalf("Failed to set %s: %v", name, err)
+		}
+	}
+
+	_, divergence, err := getBranchDivergence(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get branch divergence: %v", err)
+	}
+	topic := divergence[0]
+	if len(divergence) != 1 || topic.Ahead != 2 || topic.Behind != 2 {
+		t.Fatalf("Expected topic 2 ahead and 2 behind, got %+v", divergence)
+	}
+	if newest := time.Date(2024, 1, 1, 0, 3, 0, 0, time.UTC); !topic.MergeBase.Equal(newest) {
+		t.Errorf("Expected the newest merge base at %v, got %v", newest, topic.MergeBase)
+	}
+}
+
+func TestGetPruneCandidates(t *testing.T) {
+	repo := createBranches(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		protected []string
+		expected  []string
+	}{
+		{"merged only", start.AddDate(0, 0, 10), DefaultProtectedBranches, []string{"done: merged"}},
+		{"merged and inactive", start.AddDate(0, 0, 100), DefaultProtectedBranches, []string{"done: merged", "feature: inactive for 99 days"}},
+		{"protected", start.AddDate(0, 0, 100), []string{"master", "feat*"}, []string{"done: merged"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := getPruneCandidates(repo, inactiveAfter, tt.protected, tt.now, Options{})
+			if err != nil {
+				t.Fatalf("Failed to get stale branches: %v", err)
+			}
+			var got []string
+			for _, c := range candidates {
+				got = append(got, c.Name+": "+c.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	repo := createBranches(t)
+
+	if err := deleteBranch(repo, "done"); err != nil {
+		t.Fatalf("Failed to delete branch: %v", err)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName("done"), false); err != plumbing.ErrReferenceNotFound {
+		t.Errorf("Expected done to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Default window within which changing freshly added lines counts as rework
+const DefaultReworkWindow = 21 * 24 * time.Hour
+
+type reworkStats struct {
+	Name     string
+	Added    int // Lines added
+	Reworked int // Added lines modified or deleted again within the window
+}
+
+// Rate returns the share of added lines that were reworked
+func (r reworkStats) Rate() float64 {
+	if r.Added == 0 {
+		return 0
+	}
+	return float64(r.Reworked) / float64(r.Added)
+}
+
+type churnReport struct {
+	Window      time.Duration
+	Total       reworkStats
+	Authors     []reworkStats
+	Directories []reworkStats
+	Weeks       []reworkStats
+}
+
+// commitLines holds the lines a commit added and how many of them were
+// reworked later, per directory
+type commitLines struct {
+	Author   string
+	When     time.Time
+	Added    map[string]int
+	Reworked map[string]int
+}
+
+// AnalyzeChurn reports how much of the code added to the given repository is
+// reworked within the given window
+func AnalyzeChurn(repoPath string, window time.Duration, depth int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	churnStats(repo, window, depth, opts)
+}
+
+func churnStats(repo *git.Repository, window time.Duration, depth int, opts Options) {
+	report, err := getChurn(repo, window, depth, opts)
+	if err != nil {
+		log.Fatalf("Error getting churn: %v", err)
+	}
+	printChurn(report)
+}
+
+// getChurn walks the history oldest first and tracks the commit that wrote
+// each line. Lines a non-merge commit deletes or modifies count as rework of
+// the commit that added them when that happened within window. Results are
+// aggregated per author, per directory up to depth levels deep and per week
+// the lines were added in.
+func getChurn(repo *git.Repository, window time.Duration, depth int, opts Options) (*churnReport, error) {
+	commits := make(map[plumbing.Hash]*commitLines)
+	lines := func(hash plumbing.Hash) *commitLines {
+		cl, ok := commits[hash]
+		if !ok {
+			cl = &commitLines{Added: make(map[string]int), Reworked: make(map[string]int)}
+			commits[hash] = cl
+		}
+		return cl
+	}
+
+	_, err := forEachLineChange(repo, opts, func(c *object.Commit, changes []lineChange) error {
+		cl := lines(c.Hash)
+		cl.Author = opts.authorName(c.Author.Name, c.Author.Email)
+		cl.When = c.Author.When
+
+		for _, change := range changes {
+			if change.Added > 0 {
+				cl.Added[directoryAtDepth(change.To, depth)] += change.Added
+			}
+			dir := directoryAtDepth(change.From, depth)
+			for _, origin := range change.Deleted {
+				age := c.Author.When.Sub(origin.Author.When)
+				if age >= 0 && age <= window {
+					lines(origin.Hash).Reworked[dir]++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &churnReport{Window: window, Total: reworkStats{Name: "Total"}}
+	authors := make(map[string]*reworkStats)
+	directories := make(map[string]*reworkStats)
+	weeks := make(map[string]*reworkStats)
+	for _, cl := range commits {
+		// Lines written by merge commits have no author to attribute them to
+		if cl.Author == "" {
+			continue
+		}
+		week := periodKey(cl.When, PeriodWeek)
+		for dir, added := range cl.Added {
+			for _, s := range []*reworkStats{&report.Total, reworkStatsFor(authors, cl.Author), reworkStatsFor(directories, dir), reworkStatsFor(weeks, week)} {
+				s.Added += added
+			}
+		}
+		for dir, reworked := range cl.Reworked {
+			for _, s := range []*reworkStats{&report.Total, reworkStatsFor(authors, cl.Author), reworkStatsFor(directories, dir), reworkStatsFor(weeks, week)} {
+				s.Reworked += reworked
+			}
+		}
+	}
+
+	report.Authors = sortedReworkStats(authors, false)
+	report.Directories = sortedReworkStats(directories, false)
+	report.Weeks = sortedReworkStats(weeks, true)
+	return report, nil
+}
+
+func reworkStatsFor(stats map[string]*reworkStats, name string) *reworkStats {
+	s, ok := stats[name]
+	if !ok {
+		s = &reworkStats{Name: name}
+		stats[name] = s
+	}
+	return s
+}
+
+// sortedReworkStats sorts by name when byName is set, otherwise by the
+// number of added lines
+func sortedReworkStats(stats map[string]*reworkStats, byName bool) []reworkStats {
+	var sorted []reworkStats
+	for _, s := range stats {
+		sorted = append(sorted, *s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !byName && sorted[i].Added != sorted[j].Added {
+			return sorted[i].Added > sorted[j].Added
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func printChurn(report *churnReport) {
+	fmt.Printf("Churn analysis (rework within %d days):\n", int(report.Window.Hours()/24))
+	fmt.Printf("\nTotal: %d of %d added lines reworked (%.1f%%)\n", report.Total.Reworked, report.Total.Added, report.Total.Rate()*100)
+
+	sections := []struct {
+		title string
+		stats []reworkStats
+	}{
+		{"By author", report.Authors},
+		{"By directory", report.Directories},
+		{"By week", report.Weeks},
+	}
+	for _, section := range sections {
+		fmt.Printf("\n%s:\n", section.title)
+		for _, s := range section.stats {
+			fmt.Printf("%s: %d of %d added lines reworked (%.1f%%)\n", s.Name, s.Reworked, s.Added, s.Rate()*100)
+		}
+	}
+}
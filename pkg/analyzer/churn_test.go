@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetChurn(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: start.Add(5 * 24 * time.Hour)}
+	carol := &object.Signature{Name: "Carol", Email: "carol@example.com", When: start.Add(60 * 24 * time.Hour)}
+
+	commits := []struct {
+		author *object.Signature
+		files  map[string]string
+	}{
+		{alice, map[string]string{"src/a.go": "1\n2\n3\n4\n"}},
+		// Bob rewrites one of Alice's lines within the window
+		{bob, map[string]string{"src/a.go": "1\nb\n3\n4\n"}},
+		// Carol rewrites another one and Bob's line long after the window
+		{carol, map[string]string{"src/a.go": "1\nc\nc\n4\n"}},
+	}
+	for _, c := range commits {
+		if _, err := commitFiles(repo, "update", c.author, c.files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	report, err := getChurn(repo, DefaultReworkWindow, 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get churn: %v", err)
+	}
+
+	if report.Total != (reworkStats{Name: "Total", Added: 7, Reworked: 1}) {
+		t.Errorf("Expected 1 of 7 lines reworked, got %+v", report.Total)
+	}
+	expected := map[string]reworkStats{
+		"Alice": {Name: "Alice", Added: 4, Reworked: 1},
+		"Bob":   {Name: "Bob", Added: 1, Reworked: 0},
+		"Carol": {Name: "Carol", Added: 2, Reworked: 0},
+	}
+	for _, s := range report.Authors {
+		if s != expected[s.Name] {
+			t.Errorf("Expected %+v, got %+v", expected[s.Name], s)
+		}
+	}
+	if len(report.Directories) != 1 || report.Directories[0].Name != "src" {
+		t.Errorf("Expected a single src directory, got %+v", report.Directories)
+	}
+	if len(report.Weeks) != 2 || report.Weeks[0] != (reworkStats{Name: "2024-W01", Added: 5, Reworked: 1}) {
+		t.Errorf("Expected 2 weeks starting with 2024-W01, got %+v", report.Weeks)
+	}
+}
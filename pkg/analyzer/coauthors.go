@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Trailer crediting the co-authors of a commit, as used by GitHub
+const coauthorTrailer = "co-authored-by:"
+
+type authorCredit struct {
+	Author     string
+	Credit     float64
+	CoAuthored int // Commits the author is credited for through a trailer
+}
+
+type pairCount struct {
+	First   string
+	Second  string
+	Commits int
+}
+
+// parseCoauthors returns the co-authors named in the Co-authored-by trailers
+// of a commit message, in order of appearance. Like git, only the last
+// paragraph of the message is read as trailers, and never the subject.
+func parseCoauthors(message string) []object.Signature {
+	var coauthors []object.Signature
+	paragraphs := 0
+	inParagraph := false
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			inParagraph = false
+			continue
+		}
+		if !inParagraph {
+			inParagraph = true
+			paragraphs++
+			coauthors = nil
+		}
+		if paragraphs == 1 || len(line) < len(coauthorTrailer) || !strings.EqualFold(line[:len(coauthorTrailer)], coauthorTrailer) {
+			continue
+		}
+		value := strings.TrimSpace(line[len(coauthorTrailer):])
+		name, email, _ := strings.Cut(value, "<")
+		sig := object.Signature{Name: strings.TrimSpace(name), Email: strings.TrimSuffix(strings.TrimSpace(email), ">")}
+		if sig.Name == "" {
+			sig.Name = sig.Email
+		}
+		if sig.Name != "" {
+			coauthors = append(coauthors, sig)
+		}
+	}
+	return coauthors
+}
+
+// participantKey identifies a participant by email, ignoring case, or by
+// name when the trailer has no email
+func participantKey(sig object.Signature) string {
+	if sig.Email != "" {
+		return strings.ToLower(sig.Email)
+	}
+	return strings.ToLower(sig.Name)
+}
+
+// commitParticipants returns the names of the author and the co-authors of a
+// commit, or their teams, starting with the author. People are told apart by
+// email, so a co-author listed under another spelling of their name is
+// counted once, and so is a team several of them belong to.
+func commitParticipants(c *object.Commit, opts Options) []string {
+	var participants []string
+	seen := make(map[string]bool)
+	for _, sig := range append([]object.Signature{c.Author}, parseCoauthors(c.Message)...) {
+		name := opts.authorName(sig.Name, sig.Email)
+		key := participantKey(sig)
+		if opts.Teams != nil {
+			key = name
+		}
+		if !seen[key] {
+			seen[key] = true
+			participants = append(participants, name)
+		}
+	}
+	return participants
+}
+
+// AnalyzeCommitCredit prints the commits of the given repository credited to
+// their authors and co-authors. With fractional credit, each commit is split
+// equally between everyone who worked on it.
+func AnalyzeCommitCredit(repoPath string, fractional bool, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	commitCredit(repo, fractional, opts)
+}
+
+func commitCredit(repo *git.Repository, fractional bool, opts Options) {
+	credits, commitCount, err := getCommitCredits(repo, fractional, opts)
+	if err != nil {
+		log.Fatalf("Error getting commit credits: %v", err)
+	}
+	printCommitCredit(commitCount, credits, fractional)
+}
+
+// getCommitCredits credits every commit reachable from HEAD to its author and
+// the co-authors named in its trailers, sorted by decreasing credit
+func getCommitCredits(repo *git.Repository, fractional bool, opts Options) ([]authorCredit, int, error) {
+	credits := make(map[string]*authorCredit)
+	commitCount := 0
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		commitCount++
+		participants := commitParticipants(c, opts)
+		share := 1.0
+		if fractional {
+			share = 1 / float64(len(participants))
+		}
+		for i, name := range participants {
+			credit, ok := credits[name]
+			if !ok {
+				credit = &authorCredit{Author: name}
+				credits[name] = credit
+			}
+			credit.Credit += share
+			if i > 0 {
+				credit.CoAuthored++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sorted []authorCredit
+	for _, credit := range credits {
+		sorted = append(sorted, *credit)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Credit != sorted[j].Credit {
+			return sorted[i].Credit > sorted[j].Credit
+		}
+		return sorted[i].Author < sorted[j].Author
+	})
+	return sorted, commitCount, nil
+}
+
+func printCommitCredit(commitCount int, credits []authorCredit, fractional bool) {
+	fmt.Println("Commit history analysis (including co-authors):")
+	fmt.Printf("\nTotal number of commits: %d\n", commitCount)
+
+	fmt.Println("\nNumber of commits credited to each author (in decreasing order):")
+	for _, credit := range credits {
+		if fractional {
+			fmt.Printf("%s: %.2f commits (co-authored %d)\n", credit.Author, credit.Credit, credit.CoAuthored)
+		} else {
+			fmt.Printf("%s: %.0f commits (co-authored %d)\n", credit.Author, credit.Credit, credit.CoAuthored)
+		}
+	}
+}
+
+// AnalyzePairing prints who pairs with whom in the given repository, based on
+// the Co-authored-by trailers of its commits
+func AnalyzePairing(repoPath string, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	pairing(repo, opts)
+}
+
+func pairing(repo *git.Repository, opts Options) {
+	pairs, err := getPairs(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting pairs: %v", err)
+	}
+	printPairing(pairs)
+}
+
+// getPairs counts the commits every two people worked on together, as author
+// or co-author, sorted by decreasing count. Within a pair, names are ordered
+// alphabetically.
+func getPairs(repo *git.Repository, opts Options) ([]pairCount, error) {
+	counts := make(map[[2]string]int)
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		participants := commitParticipants(c, opts)
+		sort.Strings(participants)
+		for i := range participants {
+			for j := i + 1; j < len(participants); j++ {
+				counts[[2]string{participants[i], participants[j]}]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []pairCount
+	for pair, n := range counts {
+		pairs = append(pairs, pairCount{First: pair[0], Second: pair[1], Commits: n})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Commits != pairs[j].Commits {
+			return pairs[i].Commits > pairs[j].Commits
+		}
+		if pairs[i].First != pairs[j].First {
+			return pairs[i].First < pairs[j].First
+		}
+		return pairs[i].Second < pairs[j].Second
+	})
+	return pairs, nil
+}
+
+func printPairing(pairs []pairCount) {
+	fmt.Println("Pair programming analysis:")
+	if len(pairs) == 0 {
+		fmt.Println("\nNo co-authored commits found.")
+		return
+	}
+
+	fmt.Println("\nPairs (in decreasing order of commits together):")
+	counts := make(map[[2]string]int)
+	totals := make(map[string]int)
+	for _, p := range pairs {
+		fmt.Printf("%s & %s: %d commits\n", p.First, p.Second, p.Commits)
+		counts[[2]string{p.First, p.Second}] = p.Commits
+		counts[[2]string{p.Second, p.First}] = p.Commits
+		totals[p.First] += p.Commits
+		totals[p.Second] += p.Commits
+	}
+
+	var people []string
+	width := 0
+	for name := range totals {
+		people = append(people, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Slice(people, func(i, j int) bool {
+		if totals[people[i]] != totals[people[j]] {
+			return totals[people[i]] > totals[people[j]]
+		}
+		return people[i] < people[j]
+	})
+
+	fmt.Println("\nPairing matrix (commits together):")
+	fmt.Printf("%*s", width+4, "")
+	for i := range people {
+		fmt.Printf(" %4d", i+1)
+	}
+	fmt.Println()
+	for i, row := range people {
+		fmt.Printf("%2d %-*s ", i+1, width, row)
+		for _, column := range people {
+			if row == column {
+				fmt.Printf(" %4s", "-")
+			} else {
+				fmt.Printf(" %4d", counts[[2]string{row, column}])
+			}
+		}
+		fmt.Println()
+	}
+}
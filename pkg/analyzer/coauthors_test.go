@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func createPairedCommits(t *testing.T) *git.Repository {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	messages := []struct {
+		author  string
+		message string
+	}{
+		{"Alice", "Add parser\n\nCo-authored-by: Bob <bob@example.com>\n"},
+		{"Bob", "Fix parser\n\nco-authored-by: Alice <alice@example.com>\nCo-Authored-By: Carol <carol@example.com>\n"},
+		{"Alice", "Solo work\n"},
+	}
+	for i, m := range messages {
+		author := &object.Signature{Name: m.author, Email: m.author + "@example.com", When: time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)}
+		if _, err := commitFiles(repo, m.message, author, map[string]string{"a.txt": fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+	return repo
+}
+
+func TestParseCoauthors(t *testing.T) {
+	message := "Subject\n\nBody\n\nCo-authored-by: Bob Smith <bob@example.com>\nCo-authored-by: carol@example.com\nSigned-off-by: Alice <alice@example.com>\n"
+	expected := []object.Signature{
+		{Name: "Bob Smith", Email: "bob@example.com"},
+		{Name: "carol@example.com", Email: ""},
+	}
+	if got := parseCoauthors(message); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, want %+v", got, expected)
+	}
+
+	// Trailers quoted in the body or given as the subject do not count
+	message = "Co-authored-by: Dan <dan@example.com>\n\nCo-authored-by: Bob <bob@example.com>\nas suggested\n\nSigned-off-by: Alice <alice@example.com>\n"
+	if got := parseCoauthors(message); len(got) != 0 {
+		t.Errorf("Expected no co-authors outside the trailer block, got %+v", got)
+	}
+}
+
+func TestCommitParticipants(t *testing.T) {
+	c := &object.Commit{
+		Author:  object.Signature{Name: "Alice", Email: "alice@example.com"},
+		Message: "Subject\n\nCo-authored-by: alice <ALICE@example.com>\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: Bob B. <bob@example.com>\nCo-authored-by: Robert <robert@example.com>\n",
+	}
+	expected := []string{"Alice", "Bob", "Robert"}
+	if got := commitParticipants(c, Options{}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, want %v", got, expected)
+	}
+}
+
+func TestGetCommitCredits(t *testing.T) {
+	repo := createPairedCommits(t)
+
+	credits, commitCount, err := getCommitCredits(repo, false, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get commit credits: %v", err)
+	}
+	expected := []authorCredit{
+		{Author: "Alice", Credit: 3, CoAuthored: 1},
+		{Author: "Bob", Credit: 2, CoAuthored: 1},
+		{Author: "Carol", Credit: 1, CoAuthored: 1},
+	}
+	if commitCount != 3 || !reflect.DeepEqual(credits, expected) {
+		t.Errorf("got %d commits and %+v, want 3 and %+v", commitCount, credits, expected)
+	}
+
+	credits, _, err = getCommitCredits(repo, true, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get commit credits: %v", err)
+	}
+	total := 0.0
+	for _, credit := range credits {
+		total += credit.Credit
+	}
+	// Alice gets 1/2 + 1/3 + 1 and the shares add up to the number of commits
+	if credits[0].Author != "Alice" || credits[0].Credit < 1.83 || credits[0].Credit > 1.84 || total < 2.99 || total > 3.01 {
+		t.Errorf("Expected fractional credit adding up to 3 commits, got %+v", credits)
+	}
+}
+
+func TestGetPairs(t *testing.T) {
+	repo := createPairedCommits(t)
+
+	pairs, err := getPairs(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get pairs: %v", err)
+	}
+	expected := []pairCount{
+		{First: "Alice", Second: "Bob", Commits: 2},
+		{First: "Alice", Second: "Carol", Commits: 1},
+		{First: "Bob", Second: "Carol", Commits: 1},
+	}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("got %+v, want %+v", pairs, expected)
+	}
+}
+
+func Example_printPairing() {
+	printPairing([]pairCount{
+		{First: "Alice", Second: "Bob", Commits: 2},
+		{First: "Alice", Second: "Carol", Commits: 1},
+	})
+
+	// Output:
+	// Pair programming analysis:
+	//
+	// Pairs (in decreasing order of commits together):
+	// Alice & Bob: 2 commits
+	// Alice & Carol: 1 commits
+	//
+	// Pairing matrix (commits together):
+	//              1    2    3
+	//  1 Alice     -    2    1
+	//  2 Bob       2    -    0
+	//  3 Carol     1    0    -
+}
@@ -0,0 +1,261 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type ageDistribution struct {
+	Name  string
+	Lines map[string]int // Lines surviving at HEAD per period they were written in
+	Total int
+}
+
+func (d *ageDistribution) add(period string) {
+	if d.Lines == nil {
+		d.Lines = make(map[string]int)
+	}
+	d.Lines[period]++
+	d.Total++
+}
+
+// Maximum number of periods after the cohort the survival curve is printed for
+const maxSurvivalPeriods = 12
+
+type survivalCohort struct {
+	Period    string
+	Added     int   // Lines added during the period
+	Deleted   []int // Lines of the period deleted n periods later, indexed by n up to the last period
+	Surviving int   // Lines of the period still present at HEAD
+}
+
+// Rate returns the share of the lines added during the period that survive
+func (s survivalCohort) Rate() float64 {
+	if s.Added == 0 {
+		return 0
+	}
+	return float64(s.Surviving) / float64(s.Added)
+}
+
+// SurvivalAfter returns the share of the lines added during the period that
+// were not deleted by the end of the period n periods later
+func (s survivalCohort) SurvivalAfter(n int) float64 {
+	if s.Added == 0 {
+		return 0
+	}
+	deleted := 0
+	for i := 0; i <= n && i < len(s.Deleted); i++ {
+		deleted += s.Deleted[i]
+	}
+	return math.Max(0, float64(s.Added-deleted)/float64(s.Added))
+}
+
+type codeAgeReport struct {
+	Periods     []string
+	Overall     ageDistribution
+	Directories []ageDistribution
+	Authors     []ageDistribution
+	Survival    []survivalCohort
+}
+
+// AnalyzeCodeAge reports the age of the lines of the given repository at HEAD
+// and how long code written in each period survives
+func AnalyzeCodeAge(repoPath string, period string, depth int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	codeAge(repo, period, depth, opts)
+}
+
+func codeAge(repo *git.Repository, period string, depth int, opts Options) {
+	report, err := getCodeAge(repo, period, depth, opts)
+	if err != nil {
+		log.Fatalf("Error getting code age: %v", err)
+	}
+	printCodeAge(report)
+}
+
+// getCodeAge finds the commit that wrote every line of the text files at
+// HEAD and buckets each line by the period that commit was authored in,
+// overall, per directory up to depth levels deep and per author. The lines
+// each non-merge commit deletes or modifies are traced back to the commits
+// that wrote them, which yields for the lines written in each period how many
+// periods later they were deleted. Periods are taken in UTC.
+func getCodeAge(repo *git.Repository, period string, depth int, opts Options) (*codeAgeReport, error) {
+	if !validPeriod(period) {
+		return nil, fmt.Errorf("Unsupported period %q", period)
+	}
+
+	report := &codeAgeReport{Overall: ageDistribution{Name: "All files"}}
+	directories := make(map[string]*ageDistribution)
+	authors := make(map[string]*ageDistribution)
+	periods := make(map[string]bool)
+
+	added := make(map[string]int)
+	// Author dates of the deleting and the deleted commit of every deleted line
+	type deletion struct{ At, Origin time.Time }
+	var deletions []deletion
+	var first, last time.Time
+	atHead, err := forEachLineChange(repo, opts, func(c *object.Commit, changes []lineChange) error {
+		when := c.Author.When.UTC()
+		if first.IsZero() || when.Before(first) {
+			first = when
+		}
+		if when.After(last) {
+			last = when
+		}
+		key := periodKey(when, period)
+		periods[key] = true
+
+		for _, change := range changes {
+			added[key] += change.Added
+			for _, origin := range change.Deleted {
+				deletions = append(deletions, deletion{At: when, Origin: origin.Author.When.UTC()})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for file, origins := range atHead {
+		dir := directoryAtDepth(file, depth)
+		for _, origin := range origins {
+			if origin == nil {
+				continue
+			}
+			key := periodKey(origin.Author.When.UTC(), period)
+			periods[key] = true
+			report.Overall.add(key)
+			distributionFor(directories, dir).add(key)
+			distributionFor(authors, opts.authorName(origin.Author.Name, origin.Author.Email)).add(key)
+		}
+	}
+
+	for key := range periods {
+		report.Periods = append(report.Periods, key)
+	}
+	sort.Strings(report.Periods)
+	if first.IsZero() {
+		return report, nil
+	}
+
+	// Cohorts cover every period of the history, so that the distance
+	// between two periods is the difference of their indexes
+	allPeriods := periodRange(first, last, period)
+	index := make(map[string]int)
+	cohorts := make([]survivalCohort, len(allPeriods))
+	for i, key := range allPeriods {
+		index[key] = i
+		cohorts[i] = survivalCohort{Period: key, Added: added[key], Deleted: make([]int, len(allPeriods)-i), Surviving: report.Overall.Lines[key]}
+	}
+	for _, d := range deletions {
+		from, fromOK := index[periodKey(d.Origin, period)]
+		to, toOK := index[periodKey(d.At, period)]
+		if fromOK && toOK && to >= from {
+			cohorts[from].Deleted[to-from]++
+		}
+	}
+	for _, cohort := range cohorts {
+		if cohort.Added > 0 || cohort.Surviving > 0 {
+			report.Survival = append(report.Survival, cohort)
+		}
+	}
+	report.Directories = sortedDistributions(directories)
+	report.Authors = sortedDistributions(authors)
+	return report, nil
+}
+
+func distributionFor(distributions map[string]*ageDistribution, name string) *ageDistribution {
+	d, ok := distributions[name]
+	if !ok {
+		d = &ageDistribution{Name: name}
+		distributions[name] = d
+	}
+	return d
+}
+
+func sortedDistributions(distributions map[string]*ageDistribution) []ageDistribution {
+	var sorted []ageDistribution
+	for _, d := range distributions {
+		sorted = append(sorted, *d)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// directoryAtDepth returns the directory of a file truncated to depth levels.
+// Files at the root belong to ".".
+func directoryAtDepth(file string, depth int) string {
+	parts := strings.Split(file, "/")
+	parts = parts[:len(parts)-1]
+	if len(parts) == 0 {
+		return "."
+	}
+	if depth > 0 && len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+func printCodeAge(report *codeAgeReport) {
+	fmt.Println("Code age analysis:")
+
+	fmt.Println("\nLines at HEAD by period written:")
+	printAgeDistribution(report.Overall, report.Periods)
+
+	fmt.Println("\nBy directory:")
+	for _, d := range report.Directories {
+		fmt.Printf("%s (%d lines):", d.Name, d.Total)
+		printAgeShares(d, report.Periods)
+	}
+
+	fmt.Println("\nBy author:")
+	for _, d := range report.Authors {
+		fmt.Printf("%s (%d lines):", d.Name, d.Total)
+		printAgeShares(d, report.Periods)
+	}
+
+	fmt.Println("\nSurvival of code written in each period (share of added lines left after +n periods):")
+	for _, s := range report.Survival {
+		fmt.Printf("%s (%d lines):", s.Period, s.Added)
+		for n := 0; n < len(s.Deleted) && n < maxSurvivalPeriods; n++ {
+			fmt.Printf(" +%d %.0f%%", n, s.SurvivalAfter(n)*100)
+		}
+		fmt.Printf(", %d at HEAD (%.1f%%)\n", s.Surviving, s.Rate()*100)
+	}
+}
+
+func printAgeDistribution(d ageDistribution, periods []string) {
+	for _, period := range periods {
+		share := 0.0
+		if d.Total > 0 {
+			share = float64(d.Lines[period]) / float64(d.Total)
+		}
+		fmt.Printf("%s: %d lines (%.1f%%) %s\n", period, d.Lines[period], share*100, strings.Repeat("#", int(share*50)))
+	}
+}
+
+func printAgeShares(d ageDistribution, periods []string) {
+	for _, period := range periods {
+		if d.Lines[period] > 0 {
+			fmt.Printf(" %s %.0f%%", period, float64(d.Lines[period])/float64(d.Total)*100)
+		}
+	}
+	fmt.Println()
+}
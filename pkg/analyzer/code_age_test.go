@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetCodeAge(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)}
+
+	if _, err := commitFiles(repo, "add", alice, map[string]string{"src/a.go": "1\n2\n3\n4\n", "README": "r\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	// Bob rewrites half of Alice's lines
+	if _, err := commitFiles(repo, "rewrite", bob, map[string]string{"src/a.go": "1\n2\nx\ny\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+
+	report, err := getCodeAge(repo, PeriodYear, 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get code age: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Periods, []string{"2022", "2023"}) {
+		t.Errorf("Expected periods 2022 and 2023, got %v", report.Periods)
+	}
+	if report.Overall.Lines["2022"] != 3 || report.Overall.Lines["2023"] != 2 {
+		t.Errorf("Expected 3 lines from 2022 and 2 from 2023, got %v", report.Overall.Lines)
+	}
+	if len(report.Directories) != 2 || report.Directories[0].Name != "src" || report.Directories[0].Total != 4 {
+		t.Errorf("Expected src with 4 lines first, got %+v", report.Directories)
+	}
+	if len(report.Authors) != 2 || report.Authors[0].Name != "Alice" || report.Authors[0].Total != 3 {
+		t.Errorf("Expected Alice with 3 lines first, got %+v", report.Authors)
+	}
+
+	// Two of Alice's 2022 lines are deleted one year later
+	expected := []survivalCohort{
+		{Period: "2022", Added: 5, Deleted: []int{0, 2}, Surviving: 3},
+		{Period: "2023", Added: 2, Deleted: []int{0}, Surviving: 2},
+	}
+	if !reflect.DeepEqual(report.Survival, expected) {
+		t.Errorf("got %+v, want %+v", report.Survival, expected)
+	}
+	if first := report.Survival[0]; first.SurvivalAfter(0) != 1 || first.SurvivalAfter(1) != 0.6 {
+		t.Errorf("Expected 100%% of the 2022 lines after 0 years and 60%% after 1, got %v and %v", first.SurvivalAfter(0), first.SurvivalAfter(1))
+	}
+}
+
+func TestGetCodeAgeUTC(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// New Year's Eve in New York is already 2024 in UTC
+	est := time.FixedZone("EST", -5*60*60)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2023, 12, 31, 22, 0, 0, 0, est)}
+	if _, err := commitFiles(repo, "add", alice, map[string]string{"a.go": "1\n2\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+
+	report, err := getCodeAge(repo, PeriodYear, 1, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get code age: %v", err)
+	}
+	if !reflect.DeepEqual(report.Periods, []string{"2024"}) || report.Overall.Lines["2024"] != 2 {
+		t.Errorf("Expected 2 lines from 2024, got %v", report.Overall.Lines)
+	}
+	if len(report.Survival) != 1 || report.Survival[0].Period != "2024" || report.Survival[0].Added != 2 {
+		t.Errorf("Expected a 2024 cohort of 2 lines, got %+v", report.Survival)
+	}
+}
+
+func TestPeriodKey(t *testing.T) {
+	when := time.Date(2024, 2, 14, 12, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		PeriodWeek:    "2024-W07",
+		PeriodMonth:   "2024-02",
+		PeriodQuarter: "2024-Q1",
+		PeriodYear:    "2024",
+	}
+	for period, expected := range tests {
+		if got := periodKey(when, period); got != expected {
+			t.Errorf("periodKey(%s) = %s, want %s", period, got, expected)
+		}
+	}
+}
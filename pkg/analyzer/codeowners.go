@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Locations searched for a CODEOWNERS file, in order of precedence
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+type codeownersRule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+	regexp  *regexp.Regexp
+}
+
+type codeowners struct {
+	Path  string
+	Rules []codeownersRule
+}
+
+// loadCodeowners reads the CODEOWNERS file from the tree of a commit. It
+// returns nil when the repository has none.
+func loadCodeowners(commit *object.Commit) (*codeowners, error) {
+	for _, location := range codeownersLocations {
+		f, err := commit.File(location)
+		if err == object.ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error getting %s: %w", location, err)
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %w", location, err)
+		}
+		return parseCodeowners(location, content), nil
+	}
+	return nil, nil
+}
+
+// parseCodeowners parses the rules of a CODEOWNERS file, skipping comments,
+// blank lines and section headers
+func parseCodeowners(path string, content string) *codeowners {
+	co := &codeowners{Path: path}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line, _, _ := strings.Cut(scanner.Text(), " #")
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") {
+			continue
+		}
+		co.Rules = append(co.Rules, codeownersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			Line:    lineNumber,
+			regexp:  codeownersPatternRegexp(fields[0]),
+		})
+	}
+	return co
+}
+
+// Owners returns the owners of a file: those of the last matching rule
+func (co *codeowners) Owners(file string) []string {
+	if rule := co.Match(file); rule != nil {
+		return rule.Owners
+	}
+	return nil
+}
+
+// Match returns the last rule matching a file, or nil if none matches
+func (co *codeowners) Match(file string) *codeownersRule {
+	for i := len(co.Rules) - 1; i >= 0; i-- {
+		if co.Rules[i].Matches(file) {
+			return &co.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule pattern matches a file or one of its
+// parent directories
+func (r codeownersRule) Matches(file string) bool {
+	return r.regexp.MatchString(file)
+}
+
+// codeownersPatternRegexp converts a gitignore style CODEOWNERS pattern to a
+// regular expression. Patterns without a slash other than a trailing one
+// match at any depth, others are relative to the repository root.
+func codeownersPatternRegexp(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	return globRegexp(pattern, anchored)
+}
+
+// globRegexp converts a glob pattern to a regular expression matching paths.
+// "*" and "?" do not match slashes, "**" matches any number of directories.
+// Patterns ending in a slash or without a wildcard in their last segment
+// also match everything below them, so "docs/*" matches the files directly
+// in docs while "docs" matches all of them. Anchored patterns are relative
+// to the repository root, others match at any depth.
+func globRegexp(pattern string, anchored bool) *regexp.Regexp {
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	lastSegment := pattern[strings.LastIndex(pattern, "/")+1:]
+	directory = directory || !strings.ContainsAny(lastSegment, "*?")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("(^|.*/)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**"):
+			sb.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if directory {
+		sb.WriteString("(/.*)?")
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type authorActivity struct {
+	Name       string
+	Email      string
+	Commits    int
+	LastCommit time.Time
+}
+
+type ruleOwnership struct {
+	Rule       codeownersRule
+	Files      int      // Files at HEAD the rule is the effective rule for
+	Stale      []string // Owners without a commit to those files within the threshold
+	Unresolved []string // Team owners that cannot be matched to commit authors
+	TopAuthor  *authorActivity
+	TopShare   float64 // Share of the commits to the files made by TopAuthor
+	Declared   bool    // Whether TopAuthor is one of the declared owners
+}
+
+type codeownersReport struct {
+	Path      string
+	Files     int
+	Uncovered []string
+	Rules     []ruleOwnership
+}
+
+// AnalyzeCodeowners validates the CODEOWNERS file of the given repository
+// against the files at HEAD and the commit history
+func AnalyzeCodeowners(repoPath string, staleAfter time.Duration, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	codeownersValidation(repo, staleAfter, limit, opts)
+}
+
+func codeownersValidation(repo *git.Repository, staleAfter time.Duration, limit int, opts Options) {
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+	report, err := getCodeownersReport(repo, staleAfter, now, opts)
+	if err != nil {
+		log.Fatalf("Error validating CODEOWNERS: %v", err)
+	}
+	if report == nil {
+		fmt.Println("No CODEOWNERS file found in the root, .github/ or docs/ directory.")
+		return
+	}
+	printCodeownersReport(report, staleAfter, limit)
+}
+
+// getCodeownersReport checks which files at HEAD no rule covers, which rules
+// match no file, which owners stopped committing to their paths and whether
+// the declared owners are the people actually changing the files. It returns
+// nil when the repository has no CODEOWNERS file.
+func getCodeownersReport(repo *git.Repository, staleAfter time.Duration, now time.Time, opts Options) (*codeownersReport, error) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	co, err := loadCodeowners(head)
+	if err != nil || co == nil {
+		return nil, err
+	}
+
+	report := &codeownersReport{Path: co.Path}
+	ruleFiles := make(map[int]int)
+	files, err := head.Files()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting files at HEAD: %w", err)
+	}
+	err = files.ForEach(func(f *object.File) error {
+		report.Files++
+		rule := co.Match(f.Name)
+		if rule != nil {
+			ruleFiles[rule.Line]++
+		}
+		// A rule without owners explicitly leaves its files unowned
+		if rule == nil || len(rule.Owners) == 0 {
+			report.Uncovered = append(report.Uncovered, f.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over files: %w", err)
+	}
+	sort.Strings(report.Uncovered)
+
+	// Activity of every author on the files of each rule, keyed by rule line
+	activity := make(map[int]map[string]*authorActivity)
+	err = forEachCommit(repo, opts, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		changed, err := changedFiles(c)
+		if err != nil {
+			return err
+		}
+		seen := make(map[int]bool)
+		for _, file := range changed {
+			rule := co.Match(file)
+			if rule == nil || seen[rule.Line] {
+				continue
+			}
+			seen[rule.Line] = true
+			if activity[rule.Line] == nil {
+				activity[rule.Line] = make(map[string]*authorActivity)
+			}
+			a, ok := activity[rule.Line][c.Author.Name]
+			if !ok {
+				a = &authorActivity{Name: c.Author.Name, Email: c.Author.Email}
+				activity[rule.Line][c.Author.Name] = a
+			}
+			a.Commits++
+			if c.Author.When.After(a.LastCommit) {
+				a.LastCommit = c.Author.When
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range co.Rules {
+		report.Rules = append(report.Rules, ruleOwnershipFor(rule, ruleFiles[rule.Line], activity[rule.Line], staleAfter, now))
+	}
+	return report, nil
+}
+
+func ruleOwnershipFor(rule codeownersRule, files int, activity map[string]*authorActivity, staleAfter time.Duration, now time.Time) ruleOwnership {
+	ro := ruleOwnership{Rule: rule, Files: files}
+
+	total := 0
+	for _, a := range activity {
+		total += a.Commits
+		if ro.TopAuthor == nil || a.Commits > ro.TopAuthor.Commits ||
+			(a.Commits == ro.TopAuthor.Commits && a.Name < ro.TopAuthor.Name) {
+			ro.TopAuthor = a
+		}
+	}
+	if ro.TopAuthor != nil {
+		ro.TopShare = float64(ro.TopAuthor.Commits) / float64(total)
+	}
+
+	for _, owner := range rule.Owners {
+		if isTeamOwner(owner) {
+			ro.Unresolved = append(ro.Unresolved, owner)
+			continue
+		}
+		var last time.Time
+		for _, a := range activity {
+			if ownerMatchesAuthor(owner, a.Name, a.Email) && a.LastCommit.After(last) {
+				last = a.LastCommit
+			}
+		}
+		if now.Sub(last) > staleAfter {
+			ro.Stale = append(ro.Stale, owner)
+		}
+		if ro.TopAuthor != nil && ownerMatchesAuthor(owner, ro.TopAuthor.Name, ro.TopAuthor.Email) {
+			ro.Declared = true
+		}
+	}
+	return ro
+}
+
+// isTeamOwner reports whether an owner is a team handle such as "@org/team"
+func isTeamOwner(owner string) bool {
+	return strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
+
+// ownerMatchesAuthor matches a CODEOWNERS owner, an email address or a user
+// handle, to a commit author. Handles are compared with the author name, the
+// local part of the email address and GitHub noreply addresses.
+func ownerMatchesAuthor(owner string, name string, email string) bool {
+	email = strings.ToLower(email)
+	if !strings.HasPrefix(owner, "@") {
+		return strings.EqualFold(owner, email)
+	}
+
+	handle := strings.ToLower(strings.TrimPrefix(owner, "@"))
+	local, _, _ := strings.Cut(email, "@")
+	if _, noreplyHandle, ok := strings.Cut(local, "+"); ok && strings.HasSuffix(email, "@users.noreply.github.com") {
+		local = noreplyHandle
+	}
+	return strings.EqualFold(handle, name) || handle == local
+}
+
+func printCodeownersReport(report *codeownersReport, staleAfter time.Duration, limit int) {
+	fmt.Printf("CODEOWNERS analysis (%s):\n", report.Path)
+
+	covered := report.Files - len(report.Uncovered)
+	fmt.Printf("\nCoverage: %d of %d files covered\n", covered, report.Files)
+	if len(report.Uncovered) > 0 {
+		fmt.Println("\nFiles not covered by any rule:")
+		for i, file := range report.Uncovered {
+			if limit > 0 && i >= limit {
+				fmt.Printf("... and %d more\n", len(report.Uncovered)-limit)
+				break
+			}
+			fmt.Println(file)
+		}
+	}
+
+	fmt.Println("\nRules matching no files:")
+	unused := 0
+	for _, ro := range report.Rules {
+		if ro.Files == 0 {
+			fmt.Printf("line %d: %s\n", ro.Rule.Line, ro.Rule.Pattern)
+			unused++
+		}
+	}
+	if unused == 0 {
+		fmt.Println("None")
+	}
+
+	fmt.Printf("\nOwners without commits to their paths in the last %d days:\n", int(staleAfter.Hours()/24))
+	stale := 0
+	for _, ro := range report.Rules {
+		if ro.Files > 0 && len(ro.Stale) > 0 {
+			fmt.Printf("line %d: %s: %s\n", ro.Rule.Line, ro.Rule.Pattern, strings.Join(ro.Stale, ", "))
+			stale++
+		}
+	}
+	if stale == 0 {
+		fmt.Println("None")
+	}
+
+	fmt.Println("\nDeclared vs. actual owners:")
+	for _, ro := range report.Rules {
+		if ro.Files == 0 || ro.TopAuthor == nil {
+			continue
+		}
+		status := "matches"
+		if !ro.Declared {
+			status = "differs"
+			if len(ro.Unresolved) > 0 {
+				status = "unverified (team owners)"
+			}
+		}
+		fmt.Printf("line %d: %s: declared %s, actual %s <%s> (%.0f%% of commits) - %s\n",
+			ro.Rule.Line, ro.Rule.Pattern, strings.Join(ro.Rule.Owners, " "),
+			ro.TopAuthor.Name, ro.TopAuthor.Email, ro.TopShare*100, status)
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetCodeownersReport(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: now.Add(-200 * 24 * time.Hour)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: now.Add(-24 * time.Hour)}
+
+	commits := []struct {
+		author *object.Signature
+		files  map[string]string
+	}{
+		{alice, map[string]string{
+			".github/CODEOWNERS": "/api/ @alice\n/legacy/ @org/old-team\n/web/ bob@example.com\n",
+			"api/a.go":           "a",
+			"web/index.html":     "<html>",
+			"README.md":          "readme",
+		}},
+		{bob, map[string]string{"api/a.go": "b", "web/index.html": "<html></html>"}},
+		{bob, map[string]string{"api/a.go": "c", "web/index.html": "<html><body>"}},
+	}
+	for _, c := range commits {
+		if _, err := commitFiles(repo, "update", c.author, c.files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	report, err := getCodeownersReport(repo, 90*24*time.Hour, now, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get CODEOWNERS report: %v", err)
+	}
+	if report.Path != ".github/CODEOWNERS" {
+		t.Errorf("Expected .github/CODEOWNERS, got %s", report.Path)
+	}
+	if !reflect.DeepEqual(report.Uncovered, []string{".github/CODEOWNERS", "README.md"}) {
+		t.Errorf("Expected CODEOWNERS and README.md to be uncovered, got %v", report.Uncovered)
+	}
+	if len(report.Rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %+v", report.Rules)
+	}
+
+	api := report.Rules[0]
+	if !reflect.DeepEqual(api.Stale, []string{"@alice"}) || api.Declared || api.TopAuthor.Name != "Bob" {
+		t.Errorf("Expected stale owner @alice and Bob as actual owner of /api/, got %+v", api)
+	}
+	legacy := report.Rules[1]
+	if legacy.Files != 0 || !reflect.DeepEqual(legacy.Unresolved, []string{"@org/old-team"}) {
+		t.Errorf("Expected /legacy/ to match no files, got %+v", legacy)
+	}
+	web := report.Rules[2]
+	if len(web.Stale) != 0 || !web.Declared {
+		t.Errorf("Expected bob@example.com to actively own /web/, got %+v", web)
+	}
+}
@@ -23,19 +23,25 @@ var (
 	Active   string = "Active"
 )
 
+// Number of days without commits after which a branch is inactive
+const DefaultInactiveDays = 90
+
+// Branches without commits for this long are inactive
+const inactiveAfter = DefaultInactiveDays * 24 * time.Hour
+
 // AnalyzeCommitHistory analyzes and prints commit history of the given repository
-func AnalyzeCommitHistory(repoPath string) {
+func AnalyzeCommitHistory(repoPath string, opts Options) {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
 		log.Fatalf("Error opening repository: %v", err)
 	}
 
-	commitHistory(repo)
+	commitHistory(repo, opts)
 }
 
-func commitHistory(repo *git.Repository) {
+func commitHistory(repo *git.Repository, opts Options) {
 	// Get all authors and their commit count
-	commitCounts, commitCount, err := getCommitCounts(repo)
+	commitCounts, commitCount, err := getCommitCounts(repo, opts)
 	if err != nil {
 		log.Fatalf("Error getting commit counts: %v", err)
 	}
@@ -59,36 +65,58 @@ func getSortedAuthorCommits(commitCounts map[string]int) []authorCommit {
 	return authorCommits
 }
 
-func getCommitCounts(repo *git.Repository) (map[string]int, int, error) {
-	// Get the HEAD reference
-	ref, err := repo.Head()
-	if err != nil {
-		return nil, 0, fmt.Errorf("Error getting HEAD reference: %w", err)
-	}
-
-	// Iterate over the commit history starting from HEAD
-	commitIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+func getCommitCounts(repo *git.Repository, opts Options) (map[string]int, int, error) {
+	commitDates, err := getAuthorCommitDates(repo, opts)
 	if err != nil {
-		return nil, 0, fmt.Errorf("Error getting commit log: %w", err)
+		return nil, 0, err
 	}
 
 	// Map to track the number of commits by each author
 	commitCounts := make(map[string]int)
 	commitCount := 0
+	for author, dates := range commitDates {
+		commitCounts[author] = len(dates)
+		commitCount += len(dates)
+	}
 
-	err = commitIter.ForEach(func(c *object.Commit) error {
+	return commitCounts, commitCount, nil
+}
 
-		// Increment commit count for the author
-		commitCounts[c.Author.Name]++
-		commitCount++
+// getAuthorCommitDates returns the author dates of the commits reachable from
+// HEAD, per author or team, from newest to oldest
+func getAuthorCommitDates(repo *git.Repository, opts Options) (map[string][]time.Time, error) {
+	// Get the HEAD commit
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Iterate over the commit history starting from HEAD
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+
+	commitDates := make(map[string][]time.Time)
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if opts.skipCommit(c) {
+			return nil
+		}
+		name := opts.authorName(c.Author.Name, c.Author.Email)
+		commitDates[name] = append(commitDates[name], c.Author.When)
 		return nil
 	})
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("Error iterating over commits: %w", err)
+		return nil, fmt.Errorf("Error iterating over commits: %w", err)
 	}
 
-	return commitCounts, commitCount, nil
+	// The log is ordered by committer date, which can differ from author dates
+	for _, dates := range commitDates {
+		sort.Slice(dates, func(i, j int) bool { return dates[i].After(dates[j]) })
+	}
+
+	return commitDates, nil
 }
 
 func printCommitHistoryAnalysis(commitCount int, authorCommits []authorCommit) {
@@ -104,17 +132,17 @@ func printCommitHistoryAnalysis(commitCount int, authorCommits []authorCommit) {
 	}
 }
 
-func AnalyzeCommitSize(repoPath string) {
+func AnalyzeCommitSize(repoPath string, opts Options) {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
 		log.Fatalf("Error opening repository: %v", err)
 	}
 
-	commitSize(repo)
+	commitSize(repo, opts)
 }
 
-func commitSize(repo *git.Repository) {
-	commitCount, totalSize, err := getCommitStats(repo)
+func commitSize(repo *git.Repository, opts Options) {
+	commitCount, totalSize, err := getCommitStats(repo, opts)
 	if err != nil {
 		log.Fatalf("Error getting commit stats: %v", err)
 	}
@@ -123,15 +151,15 @@ func commitSize(repo *git.Repository) {
 	printCommitSize(commitCount, totalSize)
 }
 
-func getCommitStats(repo *git.Repository) (int, int, error) {
-	// Get the HEAD reference
-	ref, err := repo.Head()
+func getCommitStats(repo *git.Repository, opts Options) (int, int, error) {
+	// Get the HEAD commit
+	head, err := headCommit(repo, opts)
 	if err != nil {
-		return 0, 0, fmt.Errorf("Error getting HEAD reference: %w", err)
+		return 0, 0, err
 	}
 
 	// Iterate over the commit history starting from HEAD
-	commitIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash})
 	if err != nil {
 		return 0, 0, fmt.Errorf("Error getting commit log: %w", err)
 	}
@@ -140,6 +168,9 @@ func getCommitStats(repo *git.Repository) (int, int, error) {
 	commitCount := 0
 
 	err = commitIter.ForEach(func(c *object.Commit) error {
+		if opts.skipCommit(c) {
+			return nil
+		}
 		totalSize += len(c.Message) // Approximate commit size as the message length
 		commitCount++
 		return nil
@@ -158,25 +189,36 @@ func printCommitSize(commitCount int, totalSize int) {
 }
 
 // AnalyzeBranchStats analyzes and prints branch stats of the given repository
-func AnalyzeBranchStats(repoPath string) {
+func AnalyzeBranchStats(repoPath string, opts Options) {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
 		log.Fatalf("Error opening repository: %v", err)
 	}
 
-	branchStats(repo, false)
+	branchStats(repo, false, opts)
 }
 
-func branchStats(repo *git.Repository, noColor bool) {
+func branchStats(repo *git.Repository, noColor bool, opts Options) {
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+
 	// List all branches and their activity status
-	branchesMap, activeBranchCount, inactiveBranchCount, err := getBranchCounts(repo)
+	branchesMap, activeBranchCount, inactiveBranchCount, err := getBranchCounts(repo, now, opts)
 	if err != nil {
 		log.Fatalf("Error getting branch history: %v", err)
 	}
 	printBranchHistory(branchesMap, activeBranchCount, inactiveBranchCount, noColor)
+
+	base, divergence, err := getBranchDivergence(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting branch divergence: %v", err)
+	}
+	printBranchDivergence(base, divergence, now)
 }
 
-func getBranchCounts(repo *git.Repository) (map[string]string, int, int, error) {
+func getBranchCounts(repo *git.Repository, now time.Time, opts Options) (map[string]string, int, int, error) {
 	branches, err := repo.Branches()
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("Error getting branches: %w", err)
@@ -187,6 +229,10 @@ func getBranchCounts(repo *git.Repository) (map[string]string, int, int, error)
 	inactiveBranchCount := 0
 
 	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		ref, err := asOfReference(repo, ref, opts)
+		if err != nil || ref == nil {
+			return err
+		}
 		commit, err := repo.CommitObject(ref.Hash())
 		if err != nil {
 			return err
@@ -195,7 +241,7 @@ func getBranchCounts(repo *git.Repository) (map[string]string, int, int, error)
 		branchName := ref.Name().Short()
 
 		// Determine if the branch is active based on last commit date
-		isActive := time.Since(commit.Committer.When) < 90*24*time.Hour
+		isActive := now.Sub(commit.Committer.When) < inactiveAfter
 
 		branchStatus := InActive
 		if isActive {
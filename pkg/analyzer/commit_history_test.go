@@ -68,7 +68,7 @@ func Example_commitHistory() {
 		return
 	}
 
-	commitHistory(repo)
+	commitHistory(repo, Options{})
 
 	// Output:
 	// Commit history analysis:
@@ -117,7 +117,7 @@ func TestListBranches(t *testing.T) {
 		t.Fatalf("Failed to create commit for tests: %v", err)
 	}
 
-	branchesMap, activeBranchCount, inactiveBranchCount, err := getBranchCounts(repo)
+	branchesMap, activeBranchCount, inactiveBranchCount, err := getBranchCounts(repo, time.Now(), Options{})
 	if err != nil {
 		t.Fatalf("Failed to get branches for tests: %v", err)
 	}
@@ -161,6 +161,31 @@ func createCommit(repo *git.Repository, when time.Time) (plumbing.Hash, error) {
 	return h, nil
 }
 
+// commitFiles writes the given files to the worktree and commits them with
+// the given author signature
+func commitFiles(repo *git.Repository, message string, author *object.Signature, files map[string]string) (plumbing.Hash, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("Failed to get worktree: %w", err)
+	}
+	for name, content := range files {
+		err = util.WriteFile(wt.Filesystem, name, []byte(content), 0644)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("Failed to write file: %w", err)
+		}
+		_, err = wt.Add(name)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("Failed to add file: %w", err)
+		}
+	}
+
+	h, err := wt.Commit(message, &git.CommitOptions{Author: author})
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("Failed to create commit: %w", err)
+	}
+	return h, nil
+}
+
 func TestCountCommits(t *testing.T) {
 	// Create a new in-memory repository
 	fs := memfs.New()
@@ -175,7 +200,7 @@ func TestCountCommits(t *testing.T) {
 	}
 
 	// Count commits
-	commitsPerAuthor, totalCommits, err := getCommitCounts(repo)
+	commitsPerAuthor, totalCommits, err := getCommitCounts(repo, Options{})
 	if err != nil {
 		t.Fatalf("Failed to count commits: %v", err)
 	}
@@ -261,7 +286,7 @@ func Example_commitSize() {
 		fmt.Printf("Failed to create commit for tests: %v\n", err)
 		return
 	}
-	commitSize(repo)
+	commitSize(repo, Options{})
 
 	// Output:
 	// Total number of commits: 1
@@ -287,7 +312,7 @@ func TestCommitStats(t *testing.T) {
 		t.Fatalf("Failed to create commit for tests: %v", err)
 	}
 
-	commitCount, totalSize, err := getCommitStats(repo)
+	commitCount, totalSize, err := getCommitStats(repo, Options{})
 	if err != nil {
 		t.Errorf("Expected nil Error, got: %v", err)
 	}
@@ -316,7 +341,7 @@ func Example_branchStats() {
 		fmt.Printf("Failed to create commit for tests: %v\n", err)
 		return
 	}
-	branchStats(repo, true)
+	branchStats(repo, true, Options{})
 
 	// Output:
 	// Branch analysis:
@@ -326,4 +351,7 @@ func Example_branchStats() {
 	//
 	// Active branches: 1
 	// Inactive branches: 0
+	//
+	// Divergence from master:
+	// No other branches.
 }
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// Number of spaces counted as one level of indentation
+const indentWidth = 4
+
+type fileComplexity struct {
+	Lines      int // Non-blank lines of code
+	Indent     int // Sum of the indentation levels of every non-blank line
+	Cyclomatic int // Cyclomatic complexity, only computed for Go files
+}
+
+// Value returns the complexity figure used for ranking. Go files use their
+// cyclomatic complexity, every other language falls back to indentation.
+func (fc fileComplexity) Value() int {
+	if fc.Cyclomatic > 0 {
+		return fc.Cyclomatic
+	}
+	return fc.Indent
+}
+
+// measureComplexity computes the complexity metrics of a single file
+func measureComplexity(path string, content string) fileComplexity {
+	var fc fileComplexity
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fc.Lines++
+		fc.Indent += indentLevel(line)
+	}
+
+	if filepath.Ext(path) == ".go" {
+		if cyclomatic, err := goCyclomatic(path, content); err == nil {
+			fc.Cyclomatic = cyclomatic
+		}
+	}
+	return fc
+}
+
+// indentLevel returns the logical indentation of a line, counting a tab or
+// indentWidth spaces as one level
+func indentLevel(line string) int {
+	tabs, spaces := 0, 0
+	for _, r := range line {
+		switch r {
+		case '\t':
+			tabs++
+		case ' ':
+			spaces++
+		default:
+			return tabs + spaces/indentWidth
+		}
+	}
+	return tabs + spaces/indentWidth
+}
+
+// goCyclomatic returns the summed cyclomatic complexity of every function
+// in a Go source file, including function literals assigned at package level
+func goCyclomatic(path string, content string) (int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			total += funcCyclomatic(fn.Body)
+			continue
+		}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				total += funcCyclomatic(lit.Body)
+				return false
+			}
+			return true
+		})
+	}
+	return total, nil
+}
+
+// funcCyclomatic returns the cyclomatic complexity of a single function:
+// one plus the number of decision points in its body
+func funcCyclomatic(body *ast.BlockStmt) int {
+	complexity := 1
+	if body == nil {
+		return complexity
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+type contributor struct {
+	Name         string
+	First        time.Time
+	Last         time.Time
+	Commits      int
+	ActiveWeeks  int
+	SecondCommit time.Duration // Time from first to second commit, 0 without one
+}
+
+// Tenure returns the time between the first and last commit
+func (c contributor) Tenure() time.Duration {
+	return c.Last.Sub(c.First)
+}
+
+// retentionCohort holds the contributors who made their first commit in a
+// quarter, and how many of them committed in each following quarter
+type retentionCohort struct {
+	Quarter  string
+	Size     int
+	Retained []int // Indexed by the number of quarters after the first one
+}
+
+type contributorsReport struct {
+	Contributors []contributor
+	Cohorts      []retentionCohort
+}
+
+// AnalyzeContributors reports when each author of the given repository
+// started and stopped committing and how well quarterly cohorts of new
+// contributors are retained
+func AnalyzeContributors(repoPath string, quarters int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	contributors(repo, quarters, opts)
+}
+
+func contributors(repo *git.Repository, quarters int, opts Options) {
+	report, err := getContributors(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting contributors: %v", err)
+	}
+	printContributors(report, quarters)
+}
+
+// getContributors derives the activity of each author from their commit
+// dates, in UTC. A contributor is retained in a quarter when they made at
+// least one commit in it.
+func getContributors(repo *git.Repository, opts Options) (*contributorsReport, error) {
+	commitDates, err := getAuthorCommitDates(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &contributorsReport{}
+	cohorts := make(map[int]*retentionCohort)
+	lastQuarter := 0
+	for author, dates := range commitDates {
+		c := contributor{Name: author, First: dates[len(dates)-1].UTC(), Last: dates[0].UTC(), Commits: len(dates)}
+		if len(dates) > 1 {
+			c.SecondCommit = dates[len(dates)-2].Sub(dates[len(dates)-1])
+		}
+
+		weeks := make(map[string]bool)
+		active := make(map[int]bool)
+		for _, when := range dates {
+			weeks[periodKey(when.UTC(), PeriodWeek)] = true
+			active[quarterIndex(when.UTC())] = true
+		}
+		c.ActiveWeeks = len(weeks)
+		report.Contributors = append(report.Contributors, c)
+
+		first := quarterIndex(c.First)
+		cohort, ok := cohorts[first]
+		if !ok {
+			cohort = &retentionCohort{Quarter: periodKey(c.First, PeriodQuarter)}
+			cohorts[first] = cohort
+		}
+		cohort.Size++
+		for quarter := range active {
+			offset := quarter - first
+			for len(cohort.Retained) <= offset {
+				cohort.Retained = append(cohort.Retained, 0)
+			}
+			cohort.Retained[offset]++
+			if quarter > lastQuarter {
+				lastQuarter = quarter
+			}
+		}
+	}
+
+	// Every cohort covers the quarters up to the most recent one
+	for first, cohort := range cohorts {
+		for len(cohort.Retained) <= lastQuarter-first {
+			cohort.Retained = append(cohort.Retained, 0)
+		}
+		report.Cohorts = append(report.Cohorts, *cohort)
+	}
+
+	sort.Slice(report.Contributors, func(i, j int) bool {
+		if !report.Contributors[i].First.Equal(report.Contributors[j].First) {
+			return report.Contributors[i].First.Before(report.Contributors[j].First)
+		}
+		return report.Contributors[i].Name < report.Contributors[j].Name
+	})
+	sort.Slice(report.Cohorts, func(i, j int) bool {
+		return report.Cohorts[i].Quarter < report.Cohorts[j].Quarter
+	})
+	return report, nil
+}
+
+// quarterIndex numbers quarters consecutively across years
+func quarterIndex(t time.Time) int {
+	return t.Year()*4 + (int(t.Month())-1)/3
+}
+
+func days(d time.Duration) int {
+	return int(d.Hours() / 24)
+}
+
+func printContributors(report *contributorsReport, quarters int) {
+	fmt.Printf("Contributors (%d):\n\n", len(report.Contributors))
+	var secondCommits []time.Duration
+	for _, c := range report.Contributors {
+		second := "no second commit"
+		if c.Commits > 1 {
+			second = fmt.Sprintf("second commit after %d days", days(c.SecondCommit))
+			secondCommits = append(secondCommits, c.SecondCommit)
+		}
+		fmt.Printf("%s: first %s, last %s, tenure %d days, %d commits, %d active weeks, %s\n",
+			c.Name, c.First.Format("2006-01-02"), c.Last.Format("2006-01-02"), days(c.Tenure()), c.Commits, c.ActiveWeeks, second)
+	}
+
+	fmt.Println("\nOnboarding:")
+	if len(secondCommits) > 0 {
+		sort.Slice(secondCommits, func(i, j int) bool { return secondCommits[i] < secondCommits[j] })
+		fmt.Printf("Median time to second commit: %d days\n", days(secondCommits[len(secondCommits)/2]))
+	}
+	fmt.Printf("%d of %d contributors never made a second commit\n", len(report.Contributors)-len(secondCommits), len(report.Contributors))
+
+	fmt.Println("\nCohort retention (share of each quarter's new contributors committing N quarters later):")
+	for _, cohort := range report.Cohorts {
+		var sb strings.Builder
+		for n, retained := range cohort.Retained {
+			if n == quarters {
+				break
+			}
+			fmt.Fprintf(&sb, " Q+%d %3.0f%%", n, float64(retained)/float64(cohort.Size)*100)
+		}
+		fmt.Printf("%s (%d):%s\n", cohort.Quarter, cohort.Size, sb.String())
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetContributors(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	commits := []struct {
+		name string
+		when time.Time
+	}{
+		{"Alice", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Bob", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"Alice", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"Alice", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"Carol", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, c := range commits {
+		author := &object.Signature{Name: c.name, Email: c.name + "@example.com", When: c.when}
+		if _, err := commitFiles(repo, "update", author, map[string]string{"a.txt": fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	report, err := getContributors(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get contributors: %v", err)
+	}
+
+	alice := contributor{
+		Name:         "Alice",
+		First:        commits[0].when,
+		Last:         commits[3].when,
+		Commits:      3,
+		ActiveWeeks:  2,
+		SecondCommit: 3 * 24 * time.Hour,
+	}
+	if len(report.Contributors) != 3 || !reflect.DeepEqual(report.Contributors[0], alice) {
+		t.Errorf("Expected %+v first, got %+v", alice, report.Contributors)
+	}
+	if report.Contributors[1].Name != "Bob" || report.Contributors[1].SecondCommit != 0 {
+		t.Errorf("Expected Bob without a second commit, got %+v", report.Contributors[1])
+	}
+
+	expected := []retentionCohort{
+		{Quarter: "2024-Q1", Size: 2, Retained: []int{2, 0, 1}},
+		{Quarter: "2024-Q2", Size: 1, Retained: []int{1, 0}},
+	}
+	if !reflect.DeepEqual(report.Cohorts, expected) {
+		t.Errorf("got %+v, want %+v", report.Cohorts, expected)
+	}
+}
@@ -0,0 +1,271 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultFixPattern matches commit messages of bug-fix commits by their fix
+// keywords. Issue references such as "#123" are not enough, as pull request
+// numbers appear in every squash merge.
+const DefaultFixPattern = `(?i)\b(fix(e[sd]|ing)?|bugs?|defects?|hotfix|resolve[sd]?)\b`
+
+type bugIntroducingCommit struct {
+	Hash    plumbing.Hash
+	Author  string
+	When    time.Time
+	Summary string
+	Fixes   []plumbing.Hash // Fix commits whose changed lines were blamed on this commit
+}
+
+type defectDensity struct {
+	Name    string
+	Defects int // Number of bug-introducing commits
+	Commits int // Total number of commits
+}
+
+// Density returns the share of commits that introduced a bug
+func (d defectDensity) Density() float64 {
+	if d.Commits == 0 {
+		return 0
+	}
+	return float64(d.Defects) / float64(d.Commits)
+}
+
+type defectReport struct {
+	FixCommits  int
+	Introducing []bugIntroducingCommit
+	Files       []defectDensity
+	Authors     []defectDensity
+}
+
+// AnalyzeDefects identifies bug-fix commits of the given repository and the
+// commits that likely introduced the fixed bugs
+func AnalyzeDefects(repoPath string, fixPattern string, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	pattern, err := regexp.Compile(fixPattern)
+	if err != nil {
+		log.Fatalf("Error compiling fix pattern: %v", err)
+	}
+
+	defects(repo, pattern, limit, opts)
+}
+
+func defects(repo *git.Repository, pattern *regexp.Regexp, limit int, opts Options) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting defects: %v", err)
+	}
+	report, err := getDefects(repo, head, pattern, opts)
+	if err != nil {
+		log.Fatalf("Error getting defects: %v", err)
+	}
+	printDefects(report, limit)
+}
+
+// getDefects implements the SZZ algorithm: for every bug-fix commit, the
+// lines it modified or removed are blamed in the parent revision, and the
+// commits that last touched them are reported as bug-introducing. Only the
+// history leading up to the given commit is considered.
+func getDefects(repo *git.Repository, at *object.Commit, pattern *regexp.Regexp, opts Options) (*defectReport, error) {
+	report := &defectReport{}
+	introducing := make(map[plumbing.Hash]*bugIntroducingCommit)
+	fileDefects := make(map[string]map[plumbing.Hash]bool)
+	authorCommits := make(map[string]int)
+	fileCommits := make(map[string]int)
+
+	err := forEachCommitFrom(repo, opts, at, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		authorCommits[c.Author.Name]++
+
+		changes, err := commitChanges(c)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			name := change.To.Name
+			if name == "" {
+				name = change.From.Name
+			}
+			fileCommits[name]++
+		}
+
+		if c.NumParents() == 0 || !pattern.MatchString(c.Message) {
+			return nil
+		}
+		report.FixCommits++
+
+		blamed, err := blameFixedLines(c, changes)
+		if err != nil {
+			return err
+		}
+		for file, hashes := range blamed {
+			for hash := range hashes {
+				bic, ok := introducing[hash]
+				if !ok {
+					commit, err := repo.CommitObject(hash)
+					if err != nil {
+						return fmt.Errorf("Error getting commit %s: %w", hash, err)
+					}
+					bic = &bugIntroducingCommit{
+						Hash:    hash,
+						Author:  commit.Author.Name,
+						When:    commit.Author.When,
+						Summary: commitSummary(commit),
+					}
+					introducing[hash] = bic
+				}
+				if !containsHash(bic.Fixes, c.Hash) {
+					bic.Fixes = append(bic.Fixes, c.Hash)
+				}
+				if fileDefects[file] == nil {
+					fileDefects[file] = make(map[plumbing.Hash]bool)
+				}
+				fileDefects[file][hash] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authorDefects := make(map[string]int)
+	for _, bic := range introducing {
+		report.Introducing = append(report.Introducing, *bic)
+		authorDefects[bic.Author]++
+	}
+	sort.Slice(report.Introducing, func(i, j int) bool {
+		a, b := report.Introducing[i], report.Introducing[j]
+		if len(a.Fixes) != len(b.Fixes) {
+			return len(a.Fixes) > len(b.Fixes)
+		}
+		return a.When.After(b.When)
+	})
+
+	for file, hashes := range fileDefects {
+		report.Files = append(report.Files, defectDensity{Name: file, Defects: len(hashes), Commits: fileCommits[file]})
+	}
+	for author, count := range authorDefects {
+		report.Authors = append(report.Authors, defectDensity{Name: author, Defects: count, Commits: authorCommits[author]})
+	}
+	sortDefectDensities(report.Files)
+	sortDefectDensities(report.Authors)
+	return report, nil
+}
+
+// blameFixedLines blames the lines a fix commit modified or removed in its
+// parent revision, returning the blamed commits per file. Blank lines are
+// ignored since they rarely carry the defect.
+func blameFixedLines(fix *object.Commit, changes object.Changes) (map[string]map[plumbing.Hash]bool, error) {
+	parent, err := fix.Parent(0)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting parent of commit %s: %w", fix.Hash, err)
+	}
+
+	blamed := make(map[string]map[plumbing.Hash]bool)
+	for _, change := range changes {
+		// Added files have no previous lines to blame
+		if change.From.Name == "" {
+			continue
+		}
+		patch, err := change.Patch()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting patch for %s: %w", change, err)
+		}
+
+		var deleted []int
+		for _, fp := range patch.FilePatches() {
+			if !fp.IsBinary() {
+				deleted = append(deleted, chunkLineDiff(fp.Chunks()).Deleted...)
+			}
+		}
+		if len(deleted) == 0 {
+			continue
+		}
+
+		result, err := git.Blame(parent, change.From.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Error blaming %s at %s: %w", change.From.Name, parent.Hash, err)
+		}
+		for _, line := range deleted {
+			if line > len(result.Lines) || strings.TrimSpace(result.Lines[line-1].Text) == "" {
+				continue
+			}
+			if blamed[change.From.Name] == nil {
+				blamed[change.From.Name] = make(map[plumbing.Hash]bool)
+			}
+			blamed[change.From.Name][result.Lines[line-1].Hash] = true
+		}
+	}
+	return blamed, nil
+}
+
+func sortDefectDensities(densities []defectDensity) {
+	sort.Slice(densities, func(i, j int) bool {
+		if densities[i].Defects != densities[j].Defects {
+			return densities[i].Defects > densities[j].Defects
+		}
+		return densities[i].Name < densities[j].Name
+	})
+}
+
+// commitSummary returns the first line of a commit message
+func commitSummary(c *object.Commit) string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return summary
+}
+
+func containsHash(hashes []plumbing.Hash, hash plumbing.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func printDefects(report *defectReport, limit int) {
+	fmt.Println("Defect analysis:")
+	fmt.Printf("\nBug-fix commits: %d\n", report.FixCommits)
+	fmt.Printf("Bug-introducing commits: %d\n", len(report.Introducing))
+
+	fmt.Println("\nLikely bug-introducing commits:")
+	for i, bic := range report.Introducing {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s %s (%s, %s): fixed by %d commit(s)\n",
+			bic.Hash.String()[:7], bic.Summary, bic.Author, bic.When.Format("2006-01-02"), len(bic.Fixes))
+	}
+
+	fmt.Println("\nDefect density by file:")
+	for i, d := range report.Files {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: %d defects in %d commits (%.2f)\n", d.Name, d.Defects, d.Commits, d.Density())
+	}
+
+	fmt.Println("\nDefect density by author:")
+	for i, d := range report.Authors {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: %d defects in %d commits (%.2f)\n", d.Name, d.Defects, d.Commits, d.Density())
+	}
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetDefects(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: start.Add(time.Hour)}
+	carol := &object.Signature{Name: "Carol", Email: "carol@example.com", When: start.Add(2 * time.Hour)}
+
+	if _, err := commitFiles(repo, "Add calc", alice, map[string]string{"calc.go": "a\nb\nc\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	buggy, err := commitFiles(repo, "Change b", bob, map[string]string{"calc.go": "a\nB\nc\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	fix, err := commitFiles(repo, "Fix wrong value in calc (#12)", carol, map[string]string{"calc.go": "a\nb2\nc\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	// Merges count neither as changes to files nor as commits of their author
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	merger := &object.Signature{Name: "Bob", Email: "bob@example.com", When: start.Add(3 * time.Hour)}
+	_, err = wt.Commit("Merge branch 'fix'", &git.CommitOptions{Author: merger, Parents: []plumbing.Hash{fix, buggy}, AllowEmptyCommits: true})
+	if err != nil {
+		t.Fatalf("Failed to create merge commit: %v", err)
+	}
+
+	head, err := headCommit(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get HEAD commit: %v", err)
+	}
+	report, err := getDefects(repo, head, regexp.MustCompile(DefaultFixPattern), Options{})
+	if err != nil {
+		t.Fatalf("Failed to get defects: %v", err)
+	}
+
+	if report.FixCommits != 1 {
+		t.Errorf("Expected 1 fix commit, got %d", report.FixCommits)
+	}
+	if len(report.Introducing) != 1 {
+		t.Fatalf("Expected 1 bug-introducing commit, got %+v", report.Introducing)
+	}
+	bic := report.Introducing[0]
+	if bic.Hash != buggy || bic.Author != "Bob" || len(bic.Fixes) != 1 || bic.Fixes[0] != fix {
+		t.Errorf("Expected Bob's commit fixed by Carol's commit, got %+v", bic)
+	}
+
+	if len(report.Files) != 1 || report.Files[0] != (defectDensity{Name: "calc.go", Defects: 1, Commits: 3}) {
+		t.Errorf("Expected calc.go with 1 defect in 3 commits, got %+v", report.Files)
+	}
+	if len(report.Authors) != 1 || report.Authors[0] != (defectDensity{Name: "Bob", Defects: 1, Commits: 1}) {
+		t.Errorf("Expected Bob with 1 defect in 1 commit, got %+v", report.Authors)
+	}
+}
+
+func TestDefaultFixPattern(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected bool
+	}{
+		{"Fix wrong value in calc", true},
+		{"Resolved crash on startup", true},
+		{"Add login form (#123)", false},
+		{"Update docs, see #45", false},
+		{"Prefix routes", false},
+	}
+
+	pattern := regexp.MustCompile(DefaultFixPattern)
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := pattern.MatchString(tt.message); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
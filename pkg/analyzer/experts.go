@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// AnalyzeExperts lists the contributors with the deepest knowledge of the
+// files of the given repository matching a path or glob
+func AnalyzeExperts(repoPath string, pattern string, halfLife time.Duration, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	experts(repo, pattern, halfLife, limit, opts)
+}
+
+func experts(repo *git.Repository, pattern string, halfLife time.Duration, limit int, opts Options) {
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+	knowledge, err := getExperts(repo, pattern, halfLife, now, opts)
+	if err != nil {
+		log.Fatalf("Error getting experts: %v", err)
+	}
+	printExperts(pattern, knowledge, limit)
+}
+
+// getExperts ranks contributors by their recency weighted commits to the
+// matching files and the lines of those files still blamed on them at HEAD
+func getExperts(repo *git.Repository, pattern string, halfLife time.Duration, now time.Time, opts Options) ([]*contributorKnowledge, error) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	return getKnowledge(repo, head, pathMatcher(pattern), now, halfLife, opts)
+}
+
+// pathMatcher returns a function matching repository paths against a path
+// or glob relative to the repository root. Directories match every file
+// below them.
+func pathMatcher(pattern string) func(path string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if pattern == "" || pattern == "." {
+		return func(path string) bool { return true }
+	}
+	re := globRegexp(pattern, true)
+	return re.MatchString
+}
+
+func printExperts(pattern string, knowledge []*contributorKnowledge, limit int) {
+	fmt.Printf("Experts for %s:\n\n", pattern)
+	if len(knowledge) == 0 {
+		fmt.Println("No contributors found.")
+		return
+	}
+	for i, k := range knowledge {
+		if limit > 0 && i >= limit {
+			break
+		}
+		name := k.Author
+		if k.Email != "" {
+			name = fmt.Sprintf("%s <%s>", k.Author, k.Email)
+		}
+		fmt.Printf("%d. %s: score %.1f - %d commits", i+1, name, k.Score(), k.Commits)
+		if k.Commits > 0 {
+			fmt.Printf(" (last %s)", k.LastCommit.Format("2006-01-02"))
+		}
+		fmt.Printf(", %d lines at HEAD, %d files\n", k.Lines, len(k.Files))
+	}
+}
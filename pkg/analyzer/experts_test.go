@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetExperts(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	halfLife := 30 * 24 * time.Hour
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: now.Add(-60 * 24 * time.Hour)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: now}
+
+	commits := []struct {
+		author *object.Signature
+		files  map[string]string
+	}{
+		{alice, map[string]string{"pkg/a.go": "1\n2\n", "docs/readme.md": "docs\n"}},
+		{bob, map[string]string{"pkg/a.go": "1\n2\n3\n"}},
+	}
+	for _, c := range commits {
+		if _, err := commitFiles(repo, "update", c.author, c.files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	knowledge, err := getExperts(repo, "pkg", halfLife, now, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get experts: %v", err)
+	}
+	if len(knowledge) != 2 {
+		t.Fatalf("Expected 2 experts, got %v", knowledge)
+	}
+
+	// Bob's commit is recent, Alice's has decayed over two half-lives
+	if knowledge[0].Author != "Bob" || knowledge[0].Lines != 1 || knowledge[0].Weighted != 1 {
+		t.Errorf("Expected Bob first with 1 line and a full weight commit, got %+v", knowledge[0])
+	}
+	if knowledge[1].Author != "Alice" || knowledge[1].Lines != 2 || math.Abs(knowledge[1].Weighted-0.25) > 1e-9 {
+		t.Errorf("Expected Alice second with 2 lines and a quarter weight commit, got %+v", knowledge[1])
+	}
+	if len(knowledge[1].Files) != 1 {
+		t.Errorf("Expected Alice's docs to be ignored, got %v", knowledge[1].Files)
+	}
+}
+
+func TestPathMatcher(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"pkg", "pkg/analyzer/a.go", true},
+		{"pkg/analyzer/a.go", "pkg/analyzer/a.go", true},
+		{"a.go", "pkg/a.go", false},
+		{"pkg/*.go", "pkg/a.go", true},
+		{"pkg/*.go", "pkg/sub/a.go", false},
+		{"pkg/**/*.go", "pkg/sub/a.go", true},
+		{".", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := pathMatcher(tt.pattern)(tt.path); got != tt.expected {
+			t.Errorf("pathMatcher(%q)(%q) = %v, want %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type fileHistoryEntry struct {
+	Hash    plumbing.Hash
+	Author  string
+	When    time.Time
+	Path    string // Path of the file after the commit, or before it when deleted
+	From    string // Previous path when the commit renamed the file
+	Added   int
+	Deleted int
+	Summary string
+}
+
+type fileHistory struct {
+	Path    string
+	Entries []fileHistoryEntry // Newest first
+	Names   []string           // Paths the file had, newest first
+	Authors int
+}
+
+// Created returns the date of the oldest commit touching the file
+func (h *fileHistory) Created() time.Time {
+	return h.Entries[len(h.Entries)-1].When
+}
+
+// ChangesPerMonth returns the average number of commits touching the file per
+// month since it was created
+func (h *fileHistory) ChangesPerMonth(now time.Time) float64 {
+	months := now.Sub(h.Created()).Hours() / 24 / 30
+	if months < 1 {
+		months = 1
+	}
+	return float64(len(h.Entries)) / months
+}
+
+// AnalyzeFileHistory prints every commit that touched the given file in the
+// given repository, following it across renames
+func AnalyzeFileHistory(repoPath string, file string, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	fileHistoryStats(repo, file, opts)
+}
+
+func fileHistoryStats(repo *git.Repository, file string, opts Options) {
+	history, err := getFileHistory(repo, file, opts)
+	if err != nil {
+		log.Fatalf("Error getting file history: %v", err)
+	}
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+	printFileHistory(history, now)
+}
+
+// getFileHistory walks the non-merge commits from HEAD and collects the ones
+// that changed file. When a commit renamed the file, older commits are
+// followed under its previous path, like git log --follow.
+func getFileHistory(repo *git.Repository, file string, opts Options) (*fileHistory, error) {
+	history := &fileHistory{Path: file, Names: []string{file}}
+	authors := make(map[string]bool)
+	current := file
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		changes, err := commitChangesWithRenames(c)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.To.Name != current && (change.To.Name != "" || change.From.Name != current) {
+				continue
+			}
+
+			entry := fileHistoryEntry{
+				Hash:    c.Hash,
+				Author:  c.Author.Name,
+				When:    c.Author.When,
+				Path:    current,
+				Summary: commitSummary(c),
+			}
+			patch, err := change.Patch()
+			if err != nil {
+				return fmt.Errorf("Error getting patch for %s: %w", change, err)
+			}
+			for _, fp := range patch.FilePatches() {
+				if !fp.IsBinary() {
+					ld := chunkLineDiff(fp.Chunks())
+					entry.Added += len(ld.Added)
+					entry.Deleted += len(ld.Deleted)
+				}
+			}
+			if isRename(change) {
+				entry.From = change.From.Name
+				current = change.From.Name
+				history.Names = append(history.Names, current)
+			}
+			history.Entries = append(history.Entries, entry)
+			authors[c.Author.Name] = true
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Entries) == 0 {
+		return nil, fmt.Errorf("No commits found for %s", file)
+	}
+	history.Authors = len(authors)
+	return history, nil
+}
+
+func printFileHistory(history *fileHistory, now time.Time) {
+	fmt.Printf("History of %s:\n\n", history.Path)
+	for _, e := range history.Entries {
+		fmt.Printf("%s %s %s +%d/-%d %s\n", e.Hash.String()[:7], e.When.Format("2006-01-02"), e.Author, e.Added, e.Deleted, e.Summary)
+		if e.From != "" {
+			fmt.Printf("        renamed from %s\n", e.From)
+		}
+	}
+
+	fmt.Printf("\nCommits: %d\n", len(history.Entries))
+	fmt.Printf("Authors: %d\n", history.Authors)
+	fmt.Printf("Age: %d days (created %s)\n", days(now.Sub(history.Created())), history.Created().Format("2006-01-02"))
+	fmt.Printf("Change frequency: %.1f commits per month\n", history.ChangesPerMonth(now))
+	if len(history.Names) > 1 {
+		fmt.Println("Previous paths:")
+		for _, name := range history.Names[1:] {
+			fmt.Printf("- %s\n", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// createRenameHistory creates old/a.go, renames it to new/a.go and changes it
+// again afterwards
+func createRenameHistory(t *testing.T) *git.Repository {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: start.AddDate(0, 1, 0)}
+	alice2 := &object.Signature{Name: "Alice", Email: "alice@example.com", When: start.AddDate(0, 2, 0)}
+
+	content := "package a\n\nfunc A() int {\n\treturn 1\n}\n"
+	if _, err := commitFiles(repo, "Add a", alice, map[string]string{"old/a.go": content, "README": "r\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	if _, err := wt.Move("old/a.go", "new/a.go"); err != nil {
+		t.Fatalf("Failed to move file: %v", err)
+	}
+	if _, err := wt.Commit("Move a", &git.CommitOptions{Author: bob}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "Change a", alice2, map[string]string{"new/a.go": content + "\nfunc B() {}\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	return repo
+}
+
+func TestGetFileHistory(t *testing.T) {
+	repo := createRenameHistory(t)
+
+	history, err := getFileHistory(repo, "new/a.go", Options{})
+	if err != nil {
+		t.Fatalf("Failed to get file history: %v", err)
+	}
+
+	var summaries []string
+	for _, e := range history.Entries {
+		summaries = append(summaries, e.Summary)
+	}
+	if !reflect.DeepEqual(summaries, []string{"Change a", "Move a", "Add a"}) {
+		t.Errorf("Expected the history to follow the rename, got %v", summaries)
+	}
+	if history.Entries[1].From != "old/a.go" || history.Entries[2].Path != "old/a.go" || history.Entries[2].Added != 5 {
+		t.Errorf("Expected a rename from old/a.go adding 5 lines, got %+v", history.Entries)
+	}
+	if history.Authors != 2 || !reflect.DeepEqual(history.Names, []string{"new/a.go", "old/a.go"}) {
+		t.Errorf("Expected 2 authors and 2 paths, got %d and %v", history.Authors, history.Names)
+	}
+
+	if _, err := getFileHistory(repo, "missing.go", Options{}); err == nil {
+		t.Error("Expected an error for a file without history")
+	}
+}
+
+func TestGetFileChurnFollowsRenames(t *testing.T) {
+	repo := createRenameHistory(t)
+
+	head, err := headCommit(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get HEAD commit: %v", err)
+	}
+	churn, err := getFileChurn(repo, head, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get file churn: %v", err)
+	}
+	expected := map[string]int{"new/a.go": 3, "README": 1}
+	if !reflect.DeepEqual(churn, expected) {
+		t.Errorf("got %v, want %v", churn, expected)
+	}
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type functionChurn struct {
+	Package  string // Directory of the package declaring the function
+	Name     string // Function name, qualified with its receiver for methods
+	Commits  int    // Number of commits that changed the function
+	Lines    int    // Number of lines added to or deleted from the function
+	LastFile string // File the function was most recently changed in
+}
+
+// funcRange is the span of lines a function declaration occupies
+type funcRange struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// AnalyzeFunctionChurn ranks the Go functions of the given repository by how
+// often and how much they change
+func AnalyzeFunctionChurn(repoPath string, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	functionChurnStats(repo, limit, opts)
+}
+
+func functionChurnStats(repo *git.Repository, limit int, opts Options) {
+	functions, err := getFunctionChurn(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting function churn: %v", err)
+	}
+	printFunctionChurn(functions, limit)
+}
+
+// getFunctionChurn maps the lines changed by every non-merge commit onto the
+// Go functions enclosing them. Functions are identified by package and name,
+// so moving a function between files of the same package keeps its history.
+func getFunctionChurn(repo *git.Repository, opts Options) ([]functionChurn, error) {
+	churn := make(map[string]*functionChurn)
+
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		changes, err := commitChanges(c)
+		if err != nil {
+			return err
+		}
+
+		touched := make(map[string]*functionChurn)
+		for _, change := range changes {
+			if !strings.HasSuffix(change.From.Name, ".go") && !strings.HasSuffix(change.To.Name, ".go") {
+				continue
+			}
+			err := changedFunctionLines(change, touched)
+			if err != nil {
+				return err
+			}
+		}
+
+		for key, fc := range touched {
+			// History is walked newest first, so the first file seen is the latest
+			if _, ok := churn[key]; !ok {
+				churn[key] = &functionChurn{Package: fc.Package, Name: fc.Name, LastFile: fc.LastFile}
+			}
+			churn[key].Commits++
+			churn[key].Lines += fc.Lines
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []functionChurn
+	for _, fc := range churn {
+		functions = append(functions, *fc)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Commits != functions[j].Commits {
+			return functions[i].Commits > functions[j].Commits
+		}
+		if functions[i].Lines != functions[j].Lines {
+			return functions[i].Lines > functions[j].Lines
+		}
+		return functionKey(functions[i].Package, functions[i].Name) < functionKey(functions[j].Package, functions[j].Name)
+	})
+	return functions, nil
+}
+
+// changedFunctionLines records the number of lines a file change touched in
+// each function into touched
+func changedFunctionLines(change *object.Change, touched map[string]*functionChurn) error {
+	patch, err := change.Patch()
+	if err != nil {
+		return fmt.Errorf("Error getting patch for %s: %w", change, err)
+	}
+	from, to, err := change.Files()
+	if err != nil {
+		return fmt.Errorf("Error getting files for %s: %w", change, err)
+	}
+
+	for _, fp := range patch.FilePatches() {
+		if fp.IsBinary() {
+			continue
+		}
+		ld := chunkLineDiff(fp.Chunks())
+
+		if from != nil {
+			ranges, err := fileFuncRanges(from)
+			if err != nil {
+				return err
+			}
+			countFunctionLines(change.From.Name, ranges, ld.Deleted, false, touched)
+		}
+		if to != nil {
+			ranges, err := fileFuncRanges(to)
+			if err != nil {
+				return err
+			}
+			countFunctionLines(change.To.Name, ranges, ld.Added, true, touched)
+		}
+	}
+	return nil
+}
+
+// countFunctionLines attributes changed lines of a file to the functions
+// enclosing them. Files with added lines take precedence as the location of
+// a function over files it was deleted from.
+func countFunctionLines(file string, ranges []funcRange, lines []int, added bool, touched map[string]*functionChurn) {
+	pkg := path.Dir(file)
+	for _, line := range lines {
+		for _, r := range ranges {
+			if line < r.Start || line > r.End {
+				continue
+			}
+			key := functionKey(pkg, r.Name)
+			fc, ok := touched[key]
+			if !ok {
+				fc = &functionChurn{Package: pkg, Name: r.Name, LastFile: file}
+				touched[key] = fc
+			} else if added {
+				fc.LastFile = file
+			}
+			fc.Lines++
+			break
+		}
+	}
+}
+
+// fileFuncRanges returns the line spans of the functions declared in a Go
+// file. Files that do not parse yield no functions.
+func fileFuncRanges(f *object.File) ([]funcRange, error) {
+	content, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s: %w", f.Name, err)
+	}
+	return goFuncRanges(f.Name, content), nil
+}
+
+func goFuncRanges(filename string, content string) []funcRange {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var ranges []funcRange
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, funcRange{
+			Name:  funcDeclName(fn),
+			Start: fset.Position(fn.Pos()).Line,
+			End:   fset.Position(fn.End()).Line,
+		})
+	}
+	return ranges
+}
+
+// funcDeclName returns the name of a function, prefixed by its receiver type
+// for methods, e.g. "(*Repository).Head"
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return "(" + receiverTypeName(fn.Recv.List[0].Type) + ")." + fn.Name.Name
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return "?"
+}
+
+// functionKey returns the package-qualified name of a function. Functions of
+// the root package are not qualified.
+func functionKey(pkg string, name string) string {
+	if pkg == "." {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func printFunctionChurn(functions []functionChurn, limit int) {
+	fmt.Println("Function churn analysis (Go):")
+	fmt.Println("\nMost frequently changed functions:")
+	for i, fc := range functions {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: %d commits, %d lines changed (%s)\n",
+			functionKey(fc.Package, fc.Name), fc.Commits, fc.Lines, fc.LastFile)
+	}
+}
@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGoFuncRanges(t *testing.T) {
+	src := `package p
+
+type T struct{}
+
+func A() {
+}
+
+func (t *T) B() int {
+	return 1
+}
+`
+	expected := []funcRange{
+		{Name: "A", Start: 5, End: 6},
+		{Name: "(*T).B", Start: 8, End: 10},
+	}
+
+	ranges := goFuncRanges("p.go", src)
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("got %v, want %v", ranges, expected)
+	}
+}
+
+func TestGetFunctionChurn(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	author := &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Now()}
+
+	commits := []map[string]string{
+		{"p/a.go": "package p\n\nfunc A() {\n}\n\nfunc B() {\n}\n"},
+		{"p/a.go": "package p\n\nfunc A() {\n}\n\nfunc B() {\n\tprintln()\n}\n"},
+		// Move B to another file of the same package
+		{"p/a.go": "package p\n\nfunc A() {\n}\n", "p/b.go": "package p\n\nfunc B() {\n\tprintln()\n}\n"},
+	}
+	for _, files := range commits {
+		if _, err := commitFiles(repo, "update", author, files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	functions, err := getFunctionChurn(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get function churn: %v", err)
+	}
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions, got %+v", functions)
+	}
+
+	b := functions[0]
+	if b.Package != "p" || b.Name != "B" || b.Commits != 3 || b.LastFile != "p/b.go" {
+		t.Errorf("Expected B changed in 3 commits and last seen in p/b.go, got %+v", b)
+	}
+	a := functions[1]
+	if a.Name != "A" || a.Commits != 1 || a.Lines != 2 {
+		t.Errorf("Expected A changed in 1 commit with 2 lines, got %+v", a)
+	}
+}
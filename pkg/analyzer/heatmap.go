@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Shades of increasing intensity used to draw heatmap cells, and their colors
+var (
+	heatShades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+	heatColors = []*color.Color{
+		color.New(color.FgWhite),
+		color.New(color.FgBlue),
+		color.New(color.FgGreen),
+		color.New(color.FgYellow),
+		color.New(color.FgRed),
+	}
+)
+
+// Weekdays in the order heatmap rows are printed
+var heatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+type heatmap struct {
+	Name    string
+	Commits [7][24]int // Indexed by time.Weekday and hour of day
+	Total   int
+}
+
+func (h *heatmap) add(when time.Time) {
+	h.Commits[when.Weekday()][when.Hour()]++
+	h.Total++
+}
+
+func (h *heatmap) max() int {
+	maxCommits := 0
+	for _, hours := range h.Commits {
+		for _, n := range hours {
+			if n > maxCommits {
+				maxCommits = n
+			}
+		}
+	}
+	return maxCommits
+}
+
+// AnalyzeHeatmap prints when commits to the given repository are made by hour
+// of day and day of week, for the whole repository and its top authors. Times
+// are in each author's own timezone unless loc is given.
+func AnalyzeHeatmap(repoPath string, loc *time.Location, limit int, noColor bool, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	commitHeatmap(repo, loc, limit, noColor, opts)
+}
+
+func commitHeatmap(repo *git.Repository, loc *time.Location, limit int, noColor bool, opts Options) {
+	overall, authors, err := getHeatmaps(repo, loc, opts)
+	if err != nil {
+		log.Fatalf("Error getting heatmap: %v", err)
+	}
+	if loc == nil {
+		fmt.Println("Commit activity by weekday and hour (author's local time):")
+	} else {
+		fmt.Printf("Commit activity by weekday and hour (%s):\n", loc)
+	}
+	printHeatmap(overall, noColor)
+	for i, h := range authors {
+		if limit > 0 && i >= limit {
+			break
+		}
+		printHeatmap(h, noColor)
+	}
+}
+
+// getHeatmaps buckets the author dates of all commits by weekday and hour,
+// for the whole repository and per author or team, with the most active
+// authors first. A nil loc keeps the timezone recorded in each commit signature.
+func getHeatmaps(repo *git.Repository, loc *time.Location, opts Options) (*heatmap, []*heatmap, error) {
+	overall := &heatmap{Name: "All authors"}
+	byAuthor := make(map[string]*heatmap)
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		when := c.Author.When
+		if loc != nil {
+			when = when.In(loc)
+		}
+		name := opts.authorName(c.Author.Name, c.Author.Email)
+		h, ok := byAuthor[name]
+		if !ok {
+			h = &heatmap{Name: name}
+			byAuthor[name] = h
+		}
+		h.add(when)
+		overall.add(when)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var authors []*heatmap
+	for _, h := range byAuthor {
+		authors = append(authors, h)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Total != authors[j].Total {
+			return authors[i].Total > authors[j].Total
+		}
+		return authors[i].Name < authors[j].Name
+	})
+	return overall, authors, nil
+}
+
+// heatLevel scales n to an index into heatShades, keeping any activity
+// visible
+func heatLevel(n int, maxCommits int) int {
+	if n == 0 || maxCommits == 0 {
+		return 0
+	}
+	return (n*(len(heatShades)-1) + maxCommits - 1) / maxCommits
+}
+
+func printHeatmap(h *heatmap, noColor bool) {
+	fmt.Printf("\n%s (%d commits):\n", h.Name, h.Total)
+	var header strings.Builder
+	header.WriteString("    ")
+	for hour := 0; hour < 24; hour++ {
+		fmt.Fprintf(&header, "%02d", hour)
+	}
+	fmt.Println(header.String())
+
+	maxCommits := h.max()
+	for _, day := range heatmapWeekdays {
+		fmt.Printf("%s ", day.String()[:3])
+		dayTotal := 0
+		for _, n := range h.Commits[day] {
+			dayTotal += n
+			level := heatLevel(n, maxCommits)
+			if noColor {
+				fmt.Print(heatShades[level])
+			} else {
+				heatColors[level].Print(heatShades[level])
+			}
+		}
+		fmt.Printf(" %d\n", dayTotal)
+	}
+}
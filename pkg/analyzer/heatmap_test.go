@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetHeatmaps(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	// Monday 2024-01-08 at 23:00 in UTC+2 is 21:00 UTC
+	plus2 := time.FixedZone("UTC+2", 2*60*60)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2024, 1, 8, 23, 0, 0, 0, plus2)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: time.Date(2024, 1, 13, 10, 0, 0, 0, time.UTC)}
+
+	for _, author := range []*object.Signature{alice, alice, bob} {
+		if _, err := commitFiles(repo, "update", author, map[string]string{"a.txt": author.Name + author.When.String()}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	overall, authors, err := getHeatmaps(repo, nil, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get heatmaps: %v", err)
+	}
+	if overall.Total != 3 || overall.Commits[time.Monday][23] != 2 || overall.Commits[time.Saturday][10] != 1 {
+		t.Errorf("Expected commits in the authors' local time, got %+v", overall.Commits)
+	}
+	if len(authors) != 2 || authors[0].Name != "Alice" || authors[0].Total != 2 {
+		t.Errorf("Expected Alice with 2 commits first, got %+v", authors)
+	}
+
+	overall, _, err = getHeatmaps(repo, time.UTC, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get heatmaps: %v", err)
+	}
+	if overall.Commits[time.Monday][21] != 2 {
+		t.Errorf("Expected commits normalized to UTC, got %+v", overall.Commits[time.Monday])
+	}
+}
+
+func TestHeatLevel(t *testing.T) {
+	tests := []struct {
+		n, max, expected int
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{5, 10, 2},
+		{10, 10, 4},
+		{0, 0, 0},
+	}
+	for _, test := range tests {
+		if got := heatLevel(test.n, test.max); got != test.expected {
+			t.Errorf("heatLevel(%d, %d) = %d, want %d", test.n, test.max, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// lineDiff holds the line numbers touched by a change to a single file
+type lineDiff struct {
+	Added   []int // 1-based line numbers in the new version of the file
+	Deleted []int // 1-based line numbers in the old version of the file
+}
+
+// headCommit returns the commit the HEAD reference points to, or pointed to
+// at the as-of point
+func headCommit(repo *git.Repository, opts Options) (*object.Commit, error) {
+	if opts.AsOfRevision != "" {
+		return resolveAsOfRevision(repo, opts.AsOfRevision)
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting HEAD reference: %w", err)
+	}
+
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("Error getting HEAD commit: %w", err)
+	}
+	if !opts.AsOf.IsZero() {
+		c, err := lastCommitBefore(commit, opts.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			return nil, fmt.Errorf("No commits before %s", opts.AsOf.Format("2006-01-02"))
+		}
+		commit = c
+	}
+	return commit, nil
+}
+
+// forEachCommit walks the commit history starting from HEAD, skipping the
+// commits opts excludes
+func forEachCommit(repo *git.Repository, opts Options, fn func(c *object.Commit) error) error {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return err
+	}
+	return forEachCommitFrom(repo, opts, head, fn)
+}
+
+// forEachCommitFrom is like forEachCommit, starting from the given commit
+func forEachCommitFrom(repo *git.Repository, opts Options, from *object.Commit, fn func(c *object.Commit) error) error {
+	commitIter, err := repo.Log(&git.LogOptions{From: from.Hash})
+	if err != nil {
+		return fmt.Errorf("Error getting commit log: %w", err)
+	}
+
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if opts.skipCommit(c) {
+			return nil
+		}
+		return fn(c)
+	})
+	if err != nil {
+		return fmt.Errorf("Error iterating over commits: %w", err)
+	}
+	return nil
+}
+
+// commitChanges returns the tree changes a commit introduced relative to its
+// first parent. Root commits are compared against an empty tree.
+func commitChanges(c *object.Commit) (object.Changes, error) {
+	return diffFirstParent(c, nil)
+}
+
+// commitChangesWithRenames is like commitChanges, but reports files that were
+// renamed or moved as a single change from the old to the new path
+func commitChangesWithRenames(c *object.Commit) (object.Changes, error) {
+	return diffFirstParent(c, object.DefaultDiffTreeOptions)
+}
+
+func diffFirstParent(c *object.Commit, opts *object.DiffTreeOptions) (object.Changes, error) {
+	var parent *object.Commit
+	if c.NumParents() > 0 {
+		var err error
+		parent, err = c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting parent of commit %s: %w", c.Hash, err)
+		}
+	}
+	return diffCommits(parent, c, opts)
+}
+
+// diffCommits returns the tree changes from parent to c, against an empty
+// tree when parent is nil
+func diffCommits(parent *object.Commit, c *object.Commit, opts *object.DiffTreeOptions) (object.Changes, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting tree of commit %s: %w", c.Hash, err)
+	}
+
+	var parentTree *object.Tree
+	if parent != nil {
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting tree of commit %s: %w", parent.Hash, err)
+		}
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Error diffing commit %s: %w", c.Hash, err)
+	}
+	return changes, nil
+}
+
+// changedFiles returns the paths touched by a commit. Deleted files are
+// reported by their old path.
+func changedFiles(c *object.Commit) ([]string, error) {
+	changes, err := commitChanges(c)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// isRename reports whether a change moved a file to another path
+func isRename(change *object.Change) bool {
+	return change.From.Name != "" && change.To.Name != "" && change.From.Name != change.To.Name
+}
+
+// chunkLineDiff maps the chunks of a file patch to the line numbers they add
+// in the new file and delete from the old file
+func chunkLineDiff(chunks []diff.Chunk) lineDiff {
+	var ld lineDiff
+	oldLine, newLine := 1, 1
+	for _, chunk := range chunks {
+		n := countLines(chunk.Content())
+		switch chunk.Type() {
+		case diff.Equal:
+			oldLine += n
+			newLine += n
+		case diff.Add:
+			for i := 0; i < n; i++ {
+				ld.Added = append(ld.Added, newLine)
+				newLine++
+			}
+		case diff.Delete:
+			for i := 0; i < n; i++ {
+				ld.Deleted = append(ld.Deleted, oldLine)
+				oldLine++
+			}
+		}
+	}
+	return ld
+}
+
+// countLines returns the number of lines in s, counting a trailing line
+// without a newline
+func countLines(s string) int {
+	n := strings.Count(s, "\n")
+	if len(s) > 0 && !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
+}
+
+// resolveRevRange returns the commits of a revision range such as
+// "main..feature", newest first, along with the commit the range is based
+// on. In a range such as "main...feature" the base is the newest merge base
+// of both revisions, as when comparing branches on GitHub. A single revision
+// selects just that commit, based on its first parent. The base is nil when
+// the range starts at a root commit.
+func resolveRevRange(repo *git.Repository, spec string) ([]*object.Commit, *object.Commit, error) {
+	from, to, isMergeBaseRange := strings.Cut(spec, "...")
+	isRange := isMergeBaseRange
+	if !isRange {
+		from, to, isRange = strings.Cut(spec, "..")
+	}
+	if !isRange {
+		commit, err := resolveCommit(repo, spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		var base *object.Commit
+		if commit.NumParents() > 0 {
+			base, err = commit.Parent(0)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Error getting parent of commit %s: %w", commit.Hash, err)
+			}
+		}
+		return []*object.Commit{commit}, base, nil
+	}
+
+	if from == "" {
+		from = "HEAD"
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	base, err := resolveCommit(repo, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	tip, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isMergeBaseRange {
+		base, err = newestMergeBase(base, tip)
+		if err != nil {
+			return nil, nil, err
+		}
+		if base == nil {
+			return nil, nil, fmt.Errorf("No merge base between %s and %s", from, to)
+		}
+	}
+
+	excluded := make(map[plumbing.Hash]bool)
+	baseIter, err := repo.Log(&git.LogOptions{From: base.Hash})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	err = baseIter.ForEach(func(c *object.Commit) error {
+		excluded[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+
+	var commits []*object.Commit
+	tipIter, err := repo.Log(&git.LogOptions{From: tip.Hash})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	err = tipIter.ForEach(func(c *object.Commit) error {
+		if !excluded[c.Hash] {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+	return commits, base, nil
+}
+
+// newestMergeBase returns the most recently committed merge base of two
+// commits, or nil when their histories are unrelated
+func newestMergeBase(a *object.Commit, b *object.Commit) (*object.Commit, error) {
+	bases, err := a.MergeBase(b)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting merge base of %s and %s: %w", a.Hash, b.Hash, err)
+	}
+	var newest *object.Commit
+	for _, c := range bases {
+		if newest == nil || c.Committer.When.After(newest.Committer.When) {
+			newest = c
+		}
+	}
+	return newest, nil
+}
+
+// resolveCommit resolves a revision such as a branch, tag or hash to a commit
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("Error resolving revision %s: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit %s: %w", rev, err)
+	}
+	return commit, nil
+}
@@ -0,0 +1,273 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var (
+	// Subjects of squash merges made by GitHub, GitLab or git merge --squash
+	squashPattern = regexp.MustCompile(`\(#\d+\)\s*$|^Squashed commit of the following`)
+	// Default messages of merge commits, capturing the merged branch
+	mergeSourcePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^Merge branch '([^']+)'`),
+		regexp.MustCompile(`^Merge remote-tracking branch '(?:refs/remotes/)?[^/']+/([^']+)'`),
+		regexp.MustCompile(`^Merge pull request #\d+ from (\S+)`),
+	}
+)
+
+type shapeCommit struct {
+	Hash    plumbing.Hash
+	Summary string
+}
+
+type historyShape struct {
+	Branch     string
+	Commits    int
+	Merges     int
+	Squashes   int           // Non-merge commits that squash a pull request
+	BackMerges []shapeCommit // The branch merged into other branches
+	CrissCross []shapeCommit // Merges whose parents have several merge bases
+	Foxtrots   []shapeCommit // Merges that swapped the first-parent history
+}
+
+// MergeRatio returns the share of merge commits in the history
+func (s *historyShape) MergeRatio() float64 {
+	if s.Commits == 0 {
+		return 0
+	}
+	return float64(s.Merges) / float64(s.Commits)
+}
+
+// AnalyzeHistoryShape reports how branches are integrated into the current
+// branch of the given repository
+func AnalyzeHistoryShape(repoPath string, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	historyShapeStats(repo, limit, opts)
+}
+
+func historyShapeStats(repo *git.Repository, limit int, opts Options) {
+	shape, err := getHistoryShape(repo, true, opts)
+	if err != nil {
+		log.Fatalf("Error getting history shape: %v", err)
+	}
+	printHistoryShape(shape, limit)
+}
+
+// mergeSource returns the branch a merge commit merged according to its
+// default message, without its remote, or an empty string
+func mergeSource(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	for _, re := range mergeSourcePatterns {
+		if m := re.FindStringSubmatch(subject); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// mainlineNames returns the names of the branch checked out at ref. With a
+// detached HEAD, these are the local branches pointing at the same commit,
+// or main and master when there are none.
+func mainlineNames(repo *git.Repository, ref *plumbing.Reference) ([]string, error) {
+	if ref.Name().IsBranch() {
+		return []string{ref.Name().Short()}, nil
+	}
+	var names []string
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting branches: %w", err)
+	}
+	err = branches.ForEach(func(b *plumbing.Reference) error {
+		if b.Hash() == ref.Hash() {
+			names = append(names, b.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting branches: %w", err)
+	}
+	if len(names) == 0 {
+		names = []string{"main", "master"}
+	}
+	return names, nil
+}
+
+// remoteMainline returns the commits on the first-parent chains of the
+// remote-tracking branches of the mainline that are not on the mainline
+// itself, i.e. mainline history as last fetched that a merge has swapped
+func remoteMainline(repo *git.Repository, names []string, mainline map[plumbing.Hash]bool) (map[plumbing.Hash]bool, error) {
+	swapped := make(map[plumbing.Hash]bool)
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting references: %w", err)
+	}
+	var tips []plumbing.Hash
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		_, branch, _ := strings.Cut(ref.Name().Short(), "/")
+		for _, name := range names {
+			if branch == name {
+				tips = append(tips, ref.Hash())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting references: %w", err)
+	}
+
+	for _, tip := range tips {
+		c, err := repo.CommitObject(tip)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting commit object: %w", err)
+		}
+		for !mainline[c.Hash] && !swapped[c.Hash] {
+			swapped[c.Hash] = true
+			if c.NumParents() == 0 {
+				break
+			}
+			c, err = c.Parent(0)
+			if err != nil {
+				return nil, fmt.Errorf("Error getting first parent: %w", err)
+			}
+		}
+	}
+	return swapped, nil
+}
+
+// getHistoryShape classifies the commits reachable from HEAD. The mainline is
+// the first-parent chain from HEAD, named after the checked out branch.
+//   - A back-merge is a merge off the mainline that merges the mainline, by
+//     message or because its second parent is a mainline commit.
+//   - A foxtrot merge is a merge on the mainline that merged the mainline
+//     branch itself, as happens when git pull merges the remote branch into
+//     local work, making the local work the first parent. Besides the merge
+//     message, a merge whose second parent is on the first-parent chain of a
+//     remote-tracking branch of the mainline counts.
+//   - A criss-cross merge is a merge whose parents have several merge bases.
+//     Finding them needs a merge base per merge, so it is skipped unless
+//     crissCross is set.
+func getHistoryShape(repo *git.Repository, crissCross bool, opts Options) (*historyShape, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting HEAD reference: %w", err)
+	}
+	shape := &historyShape{}
+	if ref.Name().IsBranch() {
+		shape.Branch = ref.Name().Short()
+	}
+	names, err := mainlineNames(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	isMainline := func(source string) bool {
+		for _, name := range names {
+			if source == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	mainline := make(map[plumbing.Hash]bool)
+	c, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		mainline[c.Hash] = true
+		if c.NumParents() == 0 {
+			break
+		}
+		c, err = c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting first parent: %w", err)
+		}
+	}
+	swapped, err := remoteMainline(repo, names, mainline)
+	if err != nil {
+		return nil, err
+	}
+
+	err = forEachCommit(repo, opts, func(c *object.Commit) error {
+		shape.Commits++
+		summary := shapeCommit{Hash: c.Hash, Summary: commitSummary(c)}
+		if c.NumParents() < 2 {
+			if squashPattern.MatchString(summary.Summary) {
+				shape.Squashes++
+			}
+			return nil
+		}
+
+		shape.Merges++
+		source := mergeSource(c.Message)
+		if mainline[c.Hash] && (isMainline(source) || swapped[c.ParentHashes[1]]) {
+			shape.Foxtrots = append(shape.Foxtrots, summary)
+		} else if !mainline[c.Hash] && (isMainline(source) || mainline[c.ParentHashes[1]]) {
+			shape.BackMerges = append(shape.BackMerges, summary)
+		}
+		if !crissCross {
+			return nil
+		}
+
+		first, err := c.Parent(0)
+		if err != nil {
+			return fmt.Errorf("Error getting parent of commit %s: %w", c.Hash, err)
+		}
+		second, err := c.Parent(1)
+		if err != nil {
+			return fmt.Errorf("Error getting parent of commit %s: %w", c.Hash, err)
+		}
+		bases, err := first.MergeBase(second)
+		if err != nil {
+			return fmt.Errorf("Error getting merge base of commit %s: %w", c.Hash, err)
+		}
+		if len(bases) > 1 {
+			shape.CrissCross = append(shape.CrissCross, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+func printShapeCommits(title string, commits []shapeCommit, limit int) {
+	fmt.Printf("\n%s: %d\n", title, len(commits))
+	for i, c := range commits {
+		if limit > 0 && i >= limit {
+			fmt.Printf("... and %d more\n", len(commits)-limit)
+			break
+		}
+		fmt.Printf("%s %s\n", c.Hash.String()[:7], c.Summary)
+	}
+}
+
+func printHistoryShape(shape *historyShape, limit int) {
+	branch := shape.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	fmt.Printf("History shape of %s:\n", branch)
+	fmt.Printf("\nCommits: %d (%d merges, %d linear)\n", shape.Commits, shape.Merges, shape.Commits-shape.Merges)
+	fmt.Printf("Merge commits: %.1f%% of history\n", shape.MergeRatio()*100)
+	fmt.Printf("Squash merges: %d\n", shape.Squashes)
+
+	printShapeCommits(fmt.Sprintf("Back-merges of %s into other branches", branch), shape.BackMerges, limit)
+	printShapeCommits("Criss-cross merges", shape.CrissCross, limit)
+	printShapeCommits("Foxtrot merges (first-parent history swapped)", shape.Foxtrots, limit)
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// storeCommit stores a commit with an empty tree and the given parents,
+// authored seq minutes after a fixed date
+func storeCommit(t *testing.T, repo *git.Repository, seq int, message string, parents ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+	tree := &object.Tree{}
+	treeObj := repo.Storer.NewEncodedObject()
+	if err := tree.Encode(treeObj); err != nil {
+		t.Fatalf("Failed to encode tree: %v", err)
+	}
+	treeHash, err := repo.Storer.SetEncodedObject(treeObj)
+	if err != nil {
+		t.Fatalf("Failed to store tree: %v", err)
+	}
+
+	sig := object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2024, 1, 1, 0, seq, 0, 0, time.UTC)}
+	commit := &object.Commit{Author: sig, Committer: sig, Message: message, TreeHash: treeHash, ParentHashes: parents}
+	obj := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatalf("Failed to encode commit: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("Failed to store commit: %v", err)
+	}
+	return hash
+}
+
+func TestGetHistoryShape(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+
+	a := storeCommit(t, repo, 1, "Initial commit")
+	b := storeCommit(t, repo, 2, "Feature work", a)
+	c := storeCommit(t, repo, 3, "Fix parser (#12)", a)
+	d := storeCommit(t, repo, 4, "Merge branch 'master' into feature", b, c)
+	e := storeCommit(t, repo, 5, "Merge pull request #1 from alice/feature", c, d)
+	// master and topic merge each other before topic is merged again
+	g := storeCommit(t, repo, 6, "Master work", e)
+	h := storeCommit(t, repo, 7, "Topic work", e)
+	m1 := storeCommit(t, repo, 8, "Merge branch 'topic'", g, h)
+	m2 := storeCommit(t, repo, 9, "Merge remote-tracking branch 'origin/master' into topic", h, g)
+	m3 := storeCommit(t, repo, 10, "Merge branch 'topic'", m1, m2)
+	// git pull merging origin/master into local work on master
+	local := storeCommit(t, repo, 11, "Local work", m3)
+	remote := storeCommit(t, repo, 12, "Remote work", m3)
+	f := storeCommit(t, repo, 13, "Merge branch 'master' of github.com:org/repo", local, remote)
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.Master, f)); err != nil {
+		t.Fatalf("Failed to set master: %v", err)
+	}
+
+	shape, err := getHistoryShape(repo, true, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get history shape: %v", err)
+	}
+
+	if shape.Branch != "master" || shape.Commits != 13 || shape.Merges != 6 || shape.Squashes != 1 {
+		t.Errorf("Expected 13 commits, 6 merges and 1 squash on master, got %+v", shape)
+	}
+	hashes := func(commits []shapeCommit) map[plumbing.Hash]bool {
+		set := make(map[plumbing.Hash]bool)
+		for _, c := range commits {
+			set[c.Hash] = true
+		}
+		return set
+	}
+	if backMerges := hashes(shape.BackMerges); len(backMerges) != 2 || !backMerges[d] || !backMerges[m2] {
+		t.Errorf("Expected back-merges %s and %s, got %+v", d, m2, shape.BackMerges)
+	}
+	if len(shape.CrissCross) != 1 || shape.CrissCross[0].Hash != m3 {
+		t.Errorf("Expected criss-cross merge %s, got %+v", m3, shape.CrissCross)
+	}
+	if len(shape.Foxtrots) != 1 || shape.Foxtrots[0].Hash != f {
+		t.Errorf("Expected foxtrot merge %s, got %+v", f, shape.Foxtrots)
+	}
+}
+
+func TestGetHistoryShapeWithoutDefaultMessages(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+
+	a := storeCommit(t, repo, 1, "Initial commit")
+	local := storeCommit(t, repo, 2, "Local work", a)
+	remote := storeCommit(t, repo, 3, "Remote work", a)
+	f := storeCommit(t, repo, 4, "Sync with upstream", local, remote)
+	topic := storeCommit(t, repo, 5, "Topic work", a)
+	back := storeCommit(t, repo, 6, "Catch up", topic, local)
+
+	// HEAD is detached at the tip of trunk, which was last fetched at remote
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference("refs/heads/trunk", f),
+		plumbing.NewHashReference("refs/heads/topic", back),
+		plumbing.NewHashReference("refs/remotes/origin/trunk", remote),
+		plumbing.NewHashReference(plumbing.HEAD, f),
+	}
+	for _, ref := range refs {
+		if err := repo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("Failed to set reference: %v", err)
+		}
+	}
+
+	shape, err := getHistoryShape(repo, false, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get history shape: %v", err)
+	}
+	if shape.Branch != "" || len(shape.Foxtrots) != 1 || shape.Foxtrots[0].Hash != f {
+		t.Errorf("Expected foxtrot merge %s on a detached HEAD, got %+v", f, shape)
+	}
+	if len(shape.CrissCross) != 0 {
+		t.Errorf("Expected criss-cross merges to be skipped, got %+v", shape.CrissCross)
+	}
+
+	names, err := mainlineNames(repo, plumbing.NewHashReference(plumbing.HEAD, f))
+	if err != nil {
+		t.Fatalf("Failed to get mainline names: %v", err)
+	}
+	if len(names) != 1 || names[0] != "trunk" {
+		t.Errorf("Expected trunk as mainline, got %v", names)
+	}
+}
+
+func TestMergeSource(t *testing.T) {
+	tests := map[string]string{
+		"Merge branch 'main' into feature":                         "main",
+		"Merge branch 'main' of github.com:org/repo":               "main",
+		"Merge remote-tracking branch 'origin/release/1.0' into x": "release/1.0",
+		"Merge pull request #12 from alice/fix-parser\n\nFix":      "alice/fix-parser",
+		"Fix parser": "",
+	}
+	for message, expected := range tests {
+		if got := mergeSource(message); got != expected {
+			t.Errorf("mergeSource(%q) = %q, want %q", message, got, expected)
+		}
+	}
+}
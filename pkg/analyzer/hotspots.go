@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type hotspot struct {
+	Path       string
+	Churn      int // Number of commits that changed the file
+	Complexity fileComplexity
+	Score      int // Churn multiplied by complexity
+}
+
+type directoryHotspot struct {
+	Directory string // Directory, or team when rolled up by team
+	Files     int
+	Churn     int
+	Score     int
+}
+
+// AnalyzeHotspots ranks files of the given repository by churn times complexity
+func AnalyzeHotspots(repoPath string, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	hotspots(repo, limit, opts)
+}
+
+func hotspots(repo *git.Repository, limit int, opts Options) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting hotspots: %v", err)
+	}
+	ranked, err := getHotspots(repo, head, opts)
+	if err != nil {
+		log.Fatalf("Error getting hotspots: %v", err)
+	}
+	printHotspots(ranked, rollupHotspots(ranked), limit)
+	if opts.Teams != nil {
+		printTeamHotspots(rollupHotspotsBy(ranked, opts.Teams.OwnerOf), limit)
+	}
+}
+
+// getHotspots combines the change frequency of every file present at a
+// commit with its complexity measured at that commit, sorted by descending
+// score
+func getHotspots(repo *git.Repository, at *object.Commit, opts Options) ([]hotspot, error) {
+	churn, err := getFileChurn(repo, at, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := at.Files()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting files of commit %s: %w", at.Hash, err)
+	}
+
+	var ranked []hotspot
+	err = files.ForEach(func(f *object.File) error {
+		if churn[f.Name] == 0 {
+			return nil
+		}
+		if binary, err := f.IsBinary(); err != nil || binary {
+			return err
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+
+		complexity := measureComplexity(f.Name, content)
+		ranked = append(ranked, hotspot{
+			Path:       f.Name,
+			Churn:      churn[f.Name],
+			Complexity: complexity,
+			Score:      churn[f.Name] * complexity.Value(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error measuring file complexity: %w", err)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	return ranked, nil
+}
+
+// getFileChurn counts the number of non-merge commits leading up to a commit
+// that changed each file, keyed by its most recent path so that renamed
+// files keep their history
+func getFileChurn(repo *git.Repository, at *object.Commit, opts Options) (map[string]int, error) {
+	churn := make(map[string]int)
+	// Maps paths files had before a rename to their most recent path. The log
+	// is walked from the newest commit, so renames are seen before the
+	// commits that touched the old path.
+	renamed := make(map[string]string)
+	err := forEachCommitFrom(repo, opts, at, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		changes, err := commitChangesWithRenames(c)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			file := change.To.Name
+			if file == "" {
+				file = change.From.Name
+			}
+			if current, ok := renamed[file]; ok {
+				file = current
+			}
+			churn[file]++
+			if isRename(change) {
+				renamed[change.From.Name] = file
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return churn, nil
+}
+
+// rollupHotspots aggregates file hotspots by their parent directory
+func rollupHotspots(ranked []hotspot) []directoryHotspot {
+	return rollupHotspotsBy(ranked, path.Dir)
+}
+
+// rollupHotspotsBy aggregates file hotspots by the group key returns for
+// their path, leaving out files without a group
+func rollupHotspotsBy(ranked []hotspot, key func(file string) string) []directoryHotspot {
+	byDirectory := make(map[string]*directoryHotspot)
+	for _, h := range ranked {
+		dir := key(h.Path)
+		if dir == "" {
+			continue
+		}
+		d, ok := byDirectory[dir]
+		if !ok {
+			d = &directoryHotspot{Directory: dir}
+			byDirectory[dir] = d
+		}
+		d.Files++
+		d.Churn += h.Churn
+		d.Score += h.Score
+	}
+
+	var directories []directoryHotspot
+	for _, d := range byDirectory {
+		directories = append(directories, *d)
+	}
+	sort.Slice(directories, func(i, j int) bool {
+		if directories[i].Score != directories[j].Score {
+			return directories[i].Score > directories[j].Score
+		}
+		return directories[i].Directory < directories[j].Directory
+	})
+	return directories
+}
+
+func printHotspots(ranked []hotspot, directories []directoryHotspot, limit int) {
+	fmt.Println("Hotspot analysis (churn x complexity):")
+
+	fmt.Println("\nFiles:")
+	for i, h := range ranked {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: score %d (%d changes, %d lines, indent complexity %d",
+			h.Path, h.Score, h.Churn, h.Complexity.Lines, h.Complexity.Indent)
+		if h.Complexity.Cyclomatic > 0 {
+			fmt.Printf(", cyclomatic complexity %d", h.Complexity.Cyclomatic)
+		}
+		fmt.Println(")")
+	}
+
+	fmt.Println("\nDirectories:")
+	for i, d := range directories {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: score %d (%d files, %d changes)\n", d.Directory, d.Score, d.Files, d.Churn)
+	}
+}
+
+func printTeamHotspots(teamHotspots []directoryHotspot, limit int) {
+	fmt.Println("\nTeams (by owned directories):")
+	for i, t := range teamHotspots {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%s: score %d (%d files, %d changes)\n", t.Directory, t.Score, t.Files, t.Churn)
+	}
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const complexGoSource = `package main
+
+func classify(n int) string {
+	if n < 0 && n > -10 {
+		return "small negative"
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i == 1:
+			return "one"
+		default:
+		}
+	}
+	return "other"
+}
+`
+
+func TestGoCyclomatic(t *testing.T) {
+	complexity, err := goCyclomatic("main.go", complexGoSource)
+	if err != nil {
+		t.Fatalf("Failed to compute cyclomatic complexity: %v", err)
+	}
+	// 1 (function) + if + && + for + case
+	if complexity != 5 {
+		t.Errorf("Expected cyclomatic complexity 5, got %d", complexity)
+	}
+}
+
+func TestMeasureComplexity(t *testing.T) {
+	fc := measureComplexity("config.yaml", "a:\n    b:\n        c: 1\n\n\td: 2\n")
+	if fc.Lines != 4 {
+		t.Errorf("Expected 4 lines, got %d", fc.Lines)
+	}
+	if fc.Indent != 4 {
+		t.Errorf("Expected indent complexity 4, got %d", fc.Indent)
+	}
+	if fc.Cyclomatic != 0 {
+		t.Errorf("Expected no cyclomatic complexity for non-Go file, got %d", fc.Cyclomatic)
+	}
+	if fc.Value() != fc.Indent {
+		t.Errorf("Expected non-Go complexity to fall back to indentation, got %d", fc.Value())
+	}
+}
+
+func TestGetHotspots(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	author := &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Now()}
+
+	// CHANGELOG changes more often but is flat, main.go is complex
+	commits := []map[string]string{
+		{"CHANGELOG": "v1\n", "src/main.go": complexGoSource},
+		{"CHANGELOG": "v1\nv2\n"},
+		{"CHANGELOG": "v1\nv2\nv3\n", "src/main.go": complexGoSource + "\n// touched\n"},
+	}
+	for _, files := range commits {
+		if _, err := commitFiles(repo, "update", author, files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	head, err := headCommit(repo, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get HEAD commit: %v", err)
+	}
+	ranked, err := getHotspots(repo, head, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get hotspots: %v", err)
+	}
+	if len(ranked) != 2 {
+		t.Fatalf("Expected 2 hotspots, got %v", ranked)
+	}
+	if ranked[0].Path != "src/main.go" || ranked[0].Churn != 2 || ranked[0].Score != 10 {
+		t.Errorf("Expected src/main.go with churn 2 and score 10 first, got %+v", ranked[0])
+	}
+	if ranked[1].Path != "CHANGELOG" || ranked[1].Churn != 3 || ranked[1].Score != 0 {
+		t.Errorf("Expected CHANGELOG with churn 3 and score 0 second, got %+v", ranked[1])
+	}
+
+	directories := rollupHotspots(ranked)
+	if len(directories) != 2 || directories[0].Directory != "src" || directories[0].Score != 10 {
+		t.Errorf("Expected src directory to rank first, got %+v", directories)
+	}
+}
+
+func Example_printHotspots() {
+	ranked := []hotspot{
+		{Path: "src/main.go", Churn: 2, Complexity: fileComplexity{Lines: 10, Indent: 12, Cyclomatic: 5}, Score: 10},
+		{Path: "README.md", Churn: 3, Complexity: fileComplexity{Lines: 4, Indent: 1}, Score: 3},
+	}
+
+	printHotspots(ranked, rollupHotspots(ranked), 0)
+
+	// Output:
+	// Hotspot analysis (churn x complexity):
+	//
+	// Files:
+	// src/main.go: score 10 (2 changes, 10 lines, indent complexity 12, cyclomatic complexity 5)
+	// README.md: score 3 (3 changes, 4 lines, indent complexity 1)
+	//
+	// Directories:
+	// src: score 10 (1 files, 2 changes)
+	// .: score 3 (1 files, 3 changes)
+}
@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Number of surviving blamed lines worth as much as one recent commit
+const linesPerCommit = 50.0
+
+// Default half-life of the weight of a commit in knowledge scores
+const DefaultKnowledgeHalfLife = 180 * 24 * time.Hour
+
+type contributorKnowledge struct {
+	Author     string
+	Email      string
+	Commits    int             // Number of commits to the matching files
+	Weighted   float64         // Commits weighted by recency
+	Lines      int             // Lines of the matching files still blamed on the contributor
+	LastCommit time.Time       // Date of the most recent commit to the matching files
+	Files      map[string]bool // Matching files the contributor changed or owns lines of
+}
+
+// Score combines the recency weighted commit count with the surviving lines
+func (k *contributorKnowledge) Score() float64 {
+	return k.Weighted + float64(k.Lines)/linesPerCommit
+}
+
+// getKnowledge measures what each contributor knows about the files matched
+// by match, from the history leading up to at and from blaming the files in
+// the tree of at. Commits count less the older they are, their weight halving
+// every halfLife before now.
+func getKnowledge(repo *git.Repository, at *object.Commit, match func(path string) bool, now time.Time, halfLife time.Duration, opts Options) ([]*contributorKnowledge, error) {
+	knowledge := make(map[string]*contributorKnowledge)
+	contributor := func(name string, email string) *contributorKnowledge {
+		if opts.Teams != nil {
+			name, email = opts.Teams.TeamOf(name, email), ""
+		}
+		k, ok := knowledge[name]
+		if !ok {
+			k = &contributorKnowledge{Author: name, Email: email, Files: make(map[string]bool)}
+			knowledge[name] = k
+		}
+		return k
+	}
+
+	commitIter, err := repo.Log(&git.LogOptions{From: at.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		files, err := changedFiles(c)
+		if err != nil {
+			return err
+		}
+
+		var matched []string
+		for _, file := range files {
+			if match(file) {
+				matched = append(matched, file)
+			}
+		}
+		if len(matched) == 0 {
+			return nil
+		}
+
+		k := contributor(c.Author.Name, c.Author.Email)
+		k.Commits++
+		k.Weighted += recencyWeight(c.Author.When, now, halfLife)
+		if c.Author.When.After(k.LastCommit) {
+			k.LastCommit = c.Author.When
+		}
+		for _, file := range matched {
+			k.Files[file] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+
+	files, err := at.Files()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting files of commit %s: %w", at.Hash, err)
+	}
+	err = files.ForEach(func(f *object.File) error {
+		if !match(f.Name) {
+			return nil
+		}
+		if binary, err := f.IsBinary(); err != nil || binary {
+			return err
+		}
+		result, err := git.Blame(at, f.Name)
+		if err != nil {
+			return fmt.Errorf("Error blaming %s: %w", f.Name, err)
+		}
+		for _, line := range result.Lines {
+			k := contributor(line.AuthorName, line.Author)
+			k.Lines++
+			k.Files[f.Name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ranked []*contributorKnowledge
+	for _, k := range knowledge {
+		ranked = append(ranked, k)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score() != ranked[j].Score() {
+			return ranked[i].Score() > ranked[j].Score()
+		}
+		return ranked[i].Author < ranked[j].Author
+	})
+	return ranked, nil
+}
+
+// recencyWeight decays exponentially with the age of a commit
+func recencyWeight(when time.Time, now time.Time, halfLife time.Duration) float64 {
+	age := now.Sub(when)
+	if age < 0 || halfLife <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// lineOrigins holds the commit that wrote each line of every text file of a
+// revision, by path
+type lineOrigins map[string][]*object.Commit
+
+// lineChange holds what a commit did to the lines of a file
+type lineChange struct {
+	From    string           // Path before the change, empty when the file was added
+	To      string           // Path after the change, empty when the file was deleted
+	Added   int              // Lines added
+	Deleted []*object.Commit // Commits that wrote the lines deleted or modified
+}
+
+// forEachLineChange walks the history up to HEAD oldest first and tracks the
+// commit that wrote each line of every text file, as blame would. Each
+// commit's diff against its parents is applied once to the lines of its
+// parents, instead of blaming the whole history of a file again for every
+// change. fn is called with the line changes of every non-merge commit opts
+// does not skip. It returns the origins of the lines at HEAD.
+func forEachLineChange(repo *git.Repository, opts Options, fn func(c *object.Commit, changes []lineChange) error) (lineOrigins, error) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	commits := make(map[plumbing.Hash]*object.Commit)
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = c
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+
+	// Parents come before their children, and the state of a commit is
+	// dropped once all of its children were processed
+	gen := generations(commits)
+	order := make([]*object.Commit, 0, len(commits))
+	children := make(map[plumbing.Hash]int)
+	for _, c := range commits {
+		order = append(order, c)
+		for _, p := range c.ParentHashes {
+			children[p]++
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if gen[order[i].Hash] != gen[order[j].Hash] {
+			return gen[order[i].Hash] < gen[order[j].Hash]
+		}
+		return order[i].Hash.String() < order[j].Hash.String()
+	})
+
+	states := make(map[plumbing.Hash]lineOrigins)
+	for _, c := range order {
+		var parents []*object.Commit
+		for _, p := range c.ParentHashes {
+			if parent, ok := commits[p]; ok {
+				parents = append(parents, parent)
+			}
+		}
+
+		state, changes, err := applyLineChanges(c, parents, states, children)
+		if err != nil {
+			return nil, err
+		}
+		states[c.Hash] = state
+		for _, p := range parents {
+			if children[p.Hash]--; children[p.Hash] == 0 {
+				delete(states, p.Hash)
+			}
+		}
+
+		if len(parents) <= 1 && !opts.skipCommit(c) {
+			if err := fn(c, changes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return states[head.Hash], nil
+}
+
+// applyLineChanges returns the line origins of c from those of its parents,
+// along with the line changes against its first parent. Lines a merge takes
+// from another parent keep their origin there.
+func applyLineChanges(c *object.Commit, parents []*object.Commit, states map[plumbing.Hash]lineOrigins, children map[plumbing.Hash]int) (lineOrigins, []lineChange, error) {
+	var first *object.Commit
+	state := make(lineOrigins)
+	if len(parents) > 0 {
+		first = parents[0]
+		if children[first.Hash] == 1 {
+			// c is the last child, which can take over the state
+			state = states[first.Hash]
+		} else {
+			for path, origins := range states[first.Hash] {
+				state[path] = origins
+			}
+		}
+	}
+
+	changes, err := diffCommits(first, c, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lineChanges []lineChange
+	// Lines of every file changed by a merge that are new against its first parent
+	unknown := make(map[string][]*object.Commit)
+	for _, change := range changes {
+		chunks, err := changeChunks(change)
+		if err != nil {
+			return nil, nil, err
+		}
+		lc := lineChange{From: change.From.Name, To: change.To.Name}
+		var origin *object.Commit
+		if len(parents) <= 1 {
+			origin = c
+		}
+		var origins []*object.Commit
+		origins, lc.Added, lc.Deleted = applyChunks(state[change.From.Name], chunks, origin)
+		lineChanges = append(lineChanges, lc)
+
+		if lc.From != "" {
+			delete(state, lc.From)
+		}
+		if lc.To != "" && chunks != nil {
+			state[lc.To] = origins
+			if origin == nil {
+				unknown[lc.To] = origins
+			}
+		}
+	}
+
+	// Lines of a merge that are new against the first parent come from the
+	// other parents, or from the merge itself when resolving conflicts
+	for i := 1; i < len(parents) && len(unknown) > 0; i++ {
+		parent := parents[i]
+		changes, err := diffCommits(parent, c, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		changed := make(map[string]*object.Change)
+		for _, change := range changes {
+			changed[change.To.Name] = change
+		}
+		for path, origins := range unknown {
+			// Files the same as in this parent take all their lines from it
+			fromParent := states[parent.Hash][path]
+			if change, ok := changed[path]; ok {
+				chunks, err := changeChunks(change)
+				if err != nil {
+					return nil, nil, err
+				}
+				fromParent, _, _ = applyChunks(states[parent.Hash][change.From.Name], chunks, nil)
+			}
+			for j := range origins {
+				if origins[j] == nil && j < len(fromParent) {
+					origins[j] = fromParent[j]
+				}
+			}
+		}
+	}
+	for _, origins := range unknown {
+		for i := range origins {
+			if origins[i] == nil {
+				origins[i] = c
+			}
+		}
+	}
+	return state, lineChanges, nil
+}
+
+// changeChunks returns the chunks of the patch of a change, or nil when the
+// file is binary after the change
+func changeChunks(change *object.Change) ([]diff.Chunk, error) {
+	patch, err := change.Patch()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting patch for %s: %w", change, err)
+	}
+	var chunks []diff.Chunk
+	for _, fp := range patch.FilePatches() {
+		if fp.IsBinary() {
+			return nil, nil
+		}
+		chunks = append(chunks, fp.Chunks()...)
+	}
+	if chunks == nil {
+		chunks = []diff.Chunk{}
+	}
+	return chunks, nil
+}
+
+// applyChunks returns the origins of the lines of a file after the chunks
+// were applied to the lines of old, with origin for the added lines. It also
+// returns the number of added lines and the origins of the deleted lines.
+func applyChunks(old []*object.Commit, chunks []diff.Chunk, origin *object.Commit) ([]*object.Commit, int, []*object.Commit) {
+	var lines, deleted []*object.Commit
+	added, i := 0, 0
+	// at returns the origin of the nth line of old, nil when unknown
+	at := func(n int) *object.Commit {
+		if n < len(old) {
+			return old[n]
+		}
+		return nil
+	}
+	for _, chunk := range chunks {
+		n := countLines(chunk.Content())
+		switch chunk.Type() {
+		case diff.Equal:
+			for j := 0; j < n; j++ {
+				lines = append(lines, at(i))
+				i++
+			}
+		case diff.Add:
+			for j := 0; j < n; j++ {
+				lines = append(lines, origin)
+			}
+			added += n
+		case diff.Delete:
+			for j := 0; j < n; j++ {
+				if o := at(i); o != nil {
+					deleted = append(deleted, o)
+				}
+				i++
+			}
+		}
+	}
+	return lines, added, deleted
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestForEachLineChangeMerge(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	signature := func(name string, day int) *object.Signature {
+		return &object.Signature{Name: name, Email: name + "@example.com", When: start.AddDate(0, 0, day)}
+	}
+
+	base, err := commitFiles(repo, "Add a", signature("Alice", 0), map[string]string{"a.txt": "1\n2\n3\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	side, err := commitFiles(repo, "Change 2", signature("Bob", 1), map[string]string{"a.txt": "1\nb\n3\n"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	commit := func(message string, author *object.Signature, content string, parents ...plumbing.Hash) plumbing.Hash {
+		if err := util.WriteFile(wt.Filesystem, "a.txt", []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := wt.Add("a.txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		h, err := wt.Commit(message, &git.CommitOptions{Author: author, Parents: parents})
+		if err != nil {
+			t.Fatalf("Failed to create commit: %v", err)
+		}
+		return h
+	}
+	mainline := commit("Change 1", signature("Carol", 2), "c\n2\n3\n", base)
+	// The merge takes a line from each side and adds one of its own
+	commit("Merge", signature("Dan", 3), "c\nb\n3\nd\n", mainline, side)
+
+	deleted := make(map[string][]string)
+	atHead, err := forEachLineChange(repo, Options{}, func(c *object.Commit, changes []lineChange) error {
+		for _, change := range changes {
+			for _, origin := range change.Deleted {
+				deleted[c.Author.Name] = append(deleted[c.Author.Name], origin.Author.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk line changes: %v", err)
+	}
+
+	expected := []string{"Carol", "Bob", "Alice", "Dan"}
+	origins := atHead["a.txt"]
+	if len(origins) != len(expected) {
+		t.Fatalf("Expected %d lines at HEAD, got %d", len(expected), len(origins))
+	}
+	for i, name := range expected {
+		if origins[i].Author.Name != name {
+			t.Errorf("Expected line %d written by %s, got %s", i+1, name, origins[i].Author.Name)
+		}
+	}
+	if len(deleted) != 2 || len(deleted["Bob"]) != 1 || deleted["Bob"][0] != "Alice" || len(deleted["Carol"]) != 1 || deleted["Carol"][0] != "Alice" {
+		t.Errorf("Expected Bob and Carol to each modify a line of Alice, got %v", deleted)
+	}
+}
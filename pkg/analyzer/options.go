@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Options holds the settings an analysis runs with, chosen per invocation.
+// The zero value analyzes every commit of the current state, reports
+// individual authors and measures ages against the current time.
+type Options struct {
+	Bots  *BotMatcher  // When set, commits authored by the bots it matches are skipped
+	Teams *TeamMapping // When set, authors are aggregated by team
+
+	// When set, HEAD and branches are resolved to their last commit at or
+	// before this time
+	AsOf time.Time
+	// When set, HEAD is this revision and branches are resolved to their last
+	// commit at or before its commit date. Takes precedence over AsOf.
+	AsOfRevision string
+
+	// When set, ages and activity are measured against this time instead of
+	// the as-of point or the current time. Pinning it makes reports
+	// reproducible.
+	Now time.Time
+	// Measure ages and activity against the date of the latest commit instead
+	NowLatest bool
+}
+
+// skipCommit reports whether analyses should ignore a commit
+func (o Options) skipCommit(c *object.Commit) bool {
+	return o.Bots != nil && o.Bots.IsBot(c.Author.Name, c.Author.Email)
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Periods supported for bucketing dates
+const (
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// validPeriod reports whether period is one of the supported periods
+func validPeriod(period string) bool {
+	switch period {
+	case PeriodWeek, PeriodMonth, PeriodQuarter, PeriodYear:
+		return true
+	}
+	return false
+}
+
+// periodKey returns a sortable label of the period containing t, e.g.
+// "2024-W07", "2024-02", "2024-Q1" or "2024"
+func periodKey(t time.Time, period string) string {
+	switch period {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	}
+	return t.Format("2006")
+}
+
+// periodStart returns the start of the period containing t, in the location
+// of t. Weeks start on Monday.
+func periodStart(t time.Time, period string) time.Time {
+	year, month, day := t.Date()
+	switch period {
+	case PeriodWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case PeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case PeriodQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
+}
+
+// periodRange returns the keys of every period from the one containing from
+// to the one containing to, including periods without activity
+func periodRange(from time.Time, to time.Time, period string) []string {
+	var keys []string
+	last := periodKey(to, period)
+	for t := periodStart(from, period); ; {
+		key := periodKey(t, period)
+		keys = append(keys, key)
+		if key >= last {
+			return keys
+		}
+		switch period {
+		case PeriodWeek:
+			t = t.AddDate(0, 0, 7)
+		case PeriodMonth:
+			t = t.AddDate(0, 1, 0)
+		case PeriodQuarter:
+			t = t.AddDate(0, 3, 0)
+		default:
+			t = t.AddDate(1, 0, 0)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// referenceTime returns the time ages and activity are measured against
+func referenceTime(repo *git.Repository, opts Options) (time.Time, error) {
+	if opts.NowLatest {
+		return latestCommitTime(repo, opts)
+	}
+	if !opts.Now.IsZero() {
+		return opts.Now, nil
+	}
+	if opts.asOfEnabled() {
+		return asOfCutoff(repo, opts)
+	}
+	return time.Now(), nil
+}
+
+// latestCommitTime returns the most recent committer date of the commits
+// HEAD and the local branches point to, at the as-of point
+func latestCommitTime(repo *git.Repository, opts Options) (time.Time, error) {
+	head, err := headCommit(repo, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	latest := head.Committer.When
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Error getting branches: %w", err)
+	}
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		ref, err := asOfReference(repo, ref, opts)
+		if err != nil || ref == nil {
+			return err
+		}
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return err
+		}
+		if commit.Committer.When.After(latest) {
+			latest = commit.Committer.When
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Error getting commit object: %w", err)
+	}
+	return latest, nil
+}
@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReferenceTime(t *testing.T) {
+	repo := createBranches(t)
+	pinned := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if now, err := referenceTime(repo, Options{Now: pinned}); err != nil || !now.Equal(pinned) {
+		t.Errorf("Expected the pinned time %v, got %v (%v)", pinned, now, err)
+	}
+
+	// The feature branch holds a newer commit than HEAD
+	latest := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+	if now, err := referenceTime(repo, Options{NowLatest: true}); err != nil || !now.Equal(latest) {
+		t.Errorf("Expected the latest commit date %v, got %v (%v)", latest, now, err)
+	}
+}
+
+func TestGetBranchCountsPinned(t *testing.T) {
+	repo := createBranches(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, active, inactive, err := getBranchCounts(repo, start.AddDate(0, 0, 30), Options{})
+	if err != nil {
+		t.Fatalf("Failed to get branches: %v", err)
+	}
+	if active != 3 || inactive != 0 {
+		t.Errorf("Expected 3 active branches, got %d active and %d inactive", active, inactive)
+	}
+
+	_, active, inactive, err = getBranchCounts(repo, start.AddDate(1, 0, 0), Options{})
+	if err != nil {
+		t.Fatalf("Failed to get branches: %v", err)
+	}
+	if active != 0 || inactive != 3 {
+		t.Errorf("Expected 3 inactive branches, got %d active and %d inactive", active, inactive)
+	}
+}
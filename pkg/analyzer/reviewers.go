@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+type reviewerSuggestion struct {
+	Name      string
+	Score     float64
+	CodeOwner bool
+	Reasons   []string
+}
+
+// AnalyzeReviewers suggests reviewers for the commits in a revision range of
+// the given repository based on the history of the files they touch
+func AnalyzeReviewers(repoPath string, revRange string, useCodeowners bool, limit int, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	reviewers(repo, revRange, useCodeowners, limit, opts)
+}
+
+func reviewers(repo *git.Repository, revRange string, useCodeowners bool, limit int, opts Options) {
+	now, err := referenceTime(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting reference time: %v", err)
+	}
+	suggestions, err := getReviewers(repo, revRange, useCodeowners, now, opts)
+	if err != nil {
+		log.Fatalf("Error suggesting reviewers: %v", err)
+	}
+	printReviewers(revRange, suggestions, limit)
+}
+
+// getReviewers ranks the people who know the touched files best, from their
+// recent commits and surviving lines at the base of the range, excluding the
+// authors of the change. Code owners of the touched files come first when
+// useCodeowners is set and the repository has a CODEOWNERS file.
+func getReviewers(repo *git.Repository, revRange string, useCodeowners bool, now time.Time, opts Options) ([]reviewerSuggestion, error) {
+	commits, base, err := resolveRevRange(repo, revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	touched := make(map[string]bool)
+	changeAuthors := make(map[string]bool)
+	for _, c := range commits {
+		changeAuthors[c.Author.Name] = true
+		changeAuthors[strings.ToLower(c.Author.Email)] = true
+		if c.NumParents() > 1 {
+			continue
+		}
+		files, err := changedFiles(c)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			touched[file] = true
+		}
+	}
+	if base == nil || len(touched) == 0 {
+		return nil, nil
+	}
+
+	knowledge, err := getKnowledge(repo, base, func(path string) bool { return touched[path] }, now, DefaultKnowledgeHalfLife, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []reviewerSuggestion
+	byEmail := make(map[string]int)
+	for _, k := range knowledge {
+		if changeAuthors[k.Author] || changeAuthors[strings.ToLower(k.Email)] {
+			continue
+		}
+		s := reviewerSuggestion{Name: fmt.Sprintf("%s <%s>", k.Author, k.Email), Score: k.Score()}
+		if k.Commits > 0 {
+			s.Reasons = append(s.Reasons, fmt.Sprintf("%d commits to %s (last %s)",
+				k.Commits, joinFiles(k.Files), k.LastCommit.Format("2006-01-02")))
+		}
+		if k.Lines > 0 {
+			s.Reasons = append(s.Reasons, fmt.Sprintf("%d lines of the touched files", k.Lines))
+		}
+		byEmail[strings.ToLower(k.Email)] = len(suggestions)
+		suggestions = append(suggestions, s)
+	}
+
+	if useCodeowners {
+		co, err := loadCodeowners(base)
+		if err != nil {
+			return nil, err
+		}
+		if co != nil {
+			suggestions = addCodeownerReviewers(suggestions, byEmail, co, touched, changeAuthors)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].CodeOwner != suggestions[j].CodeOwner {
+			return suggestions[i].CodeOwner
+		}
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions, nil
+}
+
+// addCodeownerReviewers marks the code owners of the touched files. Owners
+// given as an email address are matched against the suggested contributors,
+// team and user handles are added as they are.
+func addCodeownerReviewers(suggestions []reviewerSuggestion, byEmail map[string]int, co *codeowners, touched map[string]bool, changeAuthors map[string]bool) []reviewerSuggestion {
+	ownedFiles := make(map[string]map[string]bool)
+	var owners []string
+	for file := range touched {
+		for _, owner := range co.Owners(file) {
+			if changeAuthors[strings.ToLower(owner)] {
+				continue
+			}
+			if ownedFiles[owner] == nil {
+				ownedFiles[owner] = make(map[string]bool)
+				owners = append(owners, owner)
+			}
+			ownedFiles[owner][file] = true
+		}
+	}
+	sort.Strings(owners)
+
+	for _, owner := range owners {
+		reason := fmt.Sprintf("code owner of %s", joinFiles(ownedFiles[owner]))
+		if i, ok := byEmail[strings.ToLower(owner)]; ok {
+			suggestions[i].CodeOwner = true
+			suggestions[i].Reasons = append(suggestions[i].Reasons, reason)
+			continue
+		}
+		suggestions = append(suggestions, reviewerSuggestion{Name: owner, CodeOwner: true, Reasons: []string{reason}})
+	}
+	return suggestions
+}
+
+// joinFiles lists a set of files in sorted order
+func joinFiles(files map[string]bool) string {
+	var names []string
+	for file := range files {
+		names = append(names, file)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func printReviewers(revRange string, suggestions []reviewerSuggestion, limit int) {
+	fmt.Printf("Suggested reviewers for %s:\n\n", revRange)
+	if len(suggestions) == 0 {
+		fmt.Println("No reviewers found.")
+		return
+	}
+	for i, s := range suggestions {
+		if limit > 0 && i >= limit {
+			break
+		}
+		fmt.Printf("%d. %s: score %.1f - %s\n", i+1, s.Name, s.Score, strings.Join(s.Reasons, "; "))
+	}
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetReviewers(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: now.Add(-400 * 24 * time.Hour)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: now.Add(-10 * 24 * time.Hour)}
+	carol := &object.Signature{Name: "Carol", Email: "carol@example.com", When: now}
+
+	commits := []struct {
+		author *object.Signature
+		files  map[string]string
+	}{
+		{alice, map[string]string{"api/a.go": "1\n2\n3\n", "CODEOWNERS": "/api/ @org/api-team dave@example.com\n"}},
+		{bob, map[string]string{"api/a.go": "1\n2\n3\n4\n"}},
+		{carol, map[string]string{"api/a.go": "1\n2\n3\n4\n5\n"}},
+	}
+	for _, c := range commits {
+		if _, err := commitFiles(repo, "update", c.author, c.files); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	suggestions, err := getReviewers(repo, "HEAD", true, now, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get reviewers: %v", err)
+	}
+
+	var names []string
+	for _, s := range suggestions {
+		names = append(names, s.Name)
+	}
+	expected := []string{"@org/api-team", "dave@example.com", "Bob <bob@example.com>", "Alice <alice@example.com>"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected reviewers %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected reviewers %v, got %v", expected, names)
+			break
+		}
+	}
+}
+
+func TestCodeownersMatch(t *testing.T) {
+	co := parseCodeowners("CODEOWNERS", `# Default owners
+*       @org/everyone
+*.go    @org/gophers
+/docs/  @org/writers # documentation
+api/**/handler_*.go @org/api
+/scripts/* @org/ops
+`)
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"README.md", "@org/everyone"},
+		{"cmd/main.go", "@org/gophers"},
+		{"docs/guide/intro.md", "@org/writers"},
+		{"pkg/docs/intro.md", "@org/everyone"},
+		{"api/handler_users.go", "@org/api"},
+		{"api/v1/users/handler_users.go", "@org/api"},
+		{"scripts/build.sh", "@org/ops"},
+		{"scripts/lib/util.sh", "@org/everyone"},
+	}
+	for _, tt := range tests {
+		owners := co.Owners(tt.file)
+		if len(owners) != 1 || owners[0] != tt.expected {
+			t.Errorf("Expected %s to be owned by %s, got %v", tt.file, tt.expected, owners)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Maximum number of points a single risk factor contributes to the score
+const riskFactorWeight = 20.0
+
+// Share of the highest scoring files considered hotspots
+const hotspotShare = 0.1
+
+type riskFactor struct {
+	Name        string
+	Score       float64 // Between 0 and riskFactorWeight
+	Explanation string
+}
+
+type riskReport struct {
+	Range   string
+	Commits int
+	Files   []string
+	Factors []riskFactor
+}
+
+// Score returns the total risk score between 0 and 100
+func (r *riskReport) Score() float64 {
+	total := 0.0
+	for _, f := range r.Factors {
+		total += f.Score
+	}
+	return total
+}
+
+// Level returns a label for the risk score
+func (r *riskReport) Level() string {
+	switch score := r.Score(); {
+	case score >= 60:
+		return "high"
+	case score >= 30:
+		return "medium"
+	}
+	return "low"
+}
+
+// AnalyzeRisk scores the risk of the commits in a revision range of the
+// given repository
+func AnalyzeRisk(repoPath string, revRange string, fixPattern string, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	pattern, err := regexp.Compile(fixPattern)
+	if err != nil {
+		log.Fatalf("Error compiling fix pattern: %v", err)
+	}
+
+	risk(repo, revRange, pattern, opts)
+}
+
+func risk(repo *git.Repository, revRange string, pattern *regexp.Regexp, opts Options) {
+	report, err := getRisk(repo, revRange, pattern, opts)
+	if err != nil {
+		log.Fatalf("Error computing risk: %v", err)
+	}
+	printRisk(report)
+}
+
+// getRisk scores a change on its size, the hotspot status of the files it
+// touches, how many people own those files, whether its authors changed them
+// before and their historical defect density
+func getRisk(repo *git.Repository, revRange string, pattern *regexp.Regexp, opts Options) (*riskReport, error) {
+	commits, base, err := resolveRevRange(repo, revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	linesChanged := 0
+	touched := make(map[string]bool)
+	authors := make(map[string]bool)
+	for _, c := range commits {
+		authors[c.Author.Name] = true
+		if c.NumParents() > 1 {
+			continue
+		}
+		stats, err := c.Stats()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting stats of commit %s: %w", c.Hash, err)
+		}
+		for _, stat := range stats {
+			linesChanged += stat.Addition + stat.Deletion
+			touched[stat.Name] = true
+		}
+	}
+
+	report := &riskReport{Range: revRange, Commits: len(commits)}
+	for file := range touched {
+		report.Files = append(report.Files, file)
+	}
+	sort.Strings(report.Files)
+	if len(report.Files) == 0 {
+		return report, nil
+	}
+
+	// Every signal is measured at the base, so that neither the range nor
+	// later commits influence it
+	fileAuthors, err := getFileAuthors(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	hotspotFiles := make(map[string]bool)
+	defects := &defectReport{}
+	if base != nil {
+		hotspotFiles, err = getHotspotFiles(repo, base, opts)
+		if err != nil {
+			return nil, err
+		}
+		defects, err = getDefects(repo, base, pattern, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report.Factors = []riskFactor{
+		sizeRisk(linesChanged, len(report.Files)),
+		hotspotRisk(report.Files, hotspotFiles),
+		ownershipRisk(report.Files, fileAuthors),
+		experienceRisk(report.Files, authors, fileAuthors),
+		defectRisk(report.Files, defects),
+	}
+	return report, nil
+}
+
+// getFileAuthors returns the authors who changed each file in the history
+// leading up to base
+func getFileAuthors(repo *git.Repository, base *object.Commit) (map[string]map[string]bool, error) {
+	fileAuthors := make(map[string]map[string]bool)
+	if base == nil {
+		return fileAuthors, nil
+	}
+
+	commitIter, err := repo.Log(&git.LogOptions{From: base.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit log: %w", err)
+	}
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		files, err := changedFiles(c)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if fileAuthors[file] == nil {
+				fileAuthors[file] = make(map[string]bool)
+			}
+			fileAuthors[file][c.Author.Name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over commits: %w", err)
+	}
+	return fileAuthors, nil
+}
+
+// getHotspotFiles returns the files ranking in the top hotspotShare of all
+// hotspots at a commit with a non-zero score
+func getHotspotFiles(repo *git.Repository, at *object.Commit, opts Options) (map[string]bool, error) {
+	ranked, err := getHotspots(repo, at, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	top := int(math.Ceil(float64(len(ranked)) * hotspotShare))
+	files := make(map[string]bool)
+	for i := 0; i < top && ranked[i].Score > 0; i++ {
+		files[ranked[i].Path] = true
+	}
+	return files, nil
+}
+
+// sizeRisk grows logarithmically, reaching its maximum at 1000 changed lines
+func sizeRisk(linesChanged int, files int) riskFactor {
+	score := math.Min(1, math.Log10(float64(linesChanged)+1)/3)
+	return riskFactor{
+		Name:        "Size",
+		Score:       score * riskFactorWeight,
+		Explanation: fmt.Sprintf("%d lines changed across %d files", linesChanged, files),
+	}
+}
+
+func hotspotRisk(files []string, hotspotFiles map[string]bool) riskFactor {
+	var hot []string
+	for _, file := range files {
+		if hotspotFiles[file] {
+			hot = append(hot, file)
+		}
+	}
+
+	explanation := fmt.Sprintf("%d of %d touched files are hotspots", len(hot), len(files))
+	if len(hot) > 0 {
+		explanation += " (" + strings.Join(hot, ", ") + ")"
+	}
+	return riskFactor{
+		Name:        "Hotspots",
+		Score:       float64(len(hot)) / float64(len(files)) * riskFactorWeight,
+		Explanation: explanation,
+	}
+}
+
+// ownershipRisk grows with the average number of people who changed each
+// file, reaching its maximum at five owners per file
+func ownershipRisk(files []string, fileAuthors map[string]map[string]bool) riskFactor {
+	owners := 0
+	for _, file := range files {
+		owners += len(fileAuthors[file])
+	}
+	average := float64(owners) / float64(len(files))
+	return riskFactor{
+		Name:        "Ownership",
+		Score:       math.Min(1, average/5) * riskFactorWeight,
+		Explanation: fmt.Sprintf("touched files have %.1f owners on average", average),
+	}
+}
+
+func experienceRisk(files []string, authors map[string]bool, fileAuthors map[string]map[string]bool) riskFactor {
+	unfamiliar := 0
+	for _, file := range files {
+		familiar := false
+		for author := range authors {
+			if fileAuthors[file][author] {
+				familiar = true
+				break
+			}
+		}
+		if !familiar {
+			unfamiliar++
+		}
+	}
+	return riskFactor{
+		Name:        "Experience",
+		Score:       float64(unfamiliar) / float64(len(files)) * riskFactorWeight,
+		Explanation: fmt.Sprintf("authors never changed %d of %d touched files before", unfamiliar, len(files)),
+	}
+}
+
+// defectRisk grows with the average defect density of the touched files,
+// reaching its maximum when half of their commits introduced bugs
+func defectRisk(files []string, report *defectReport) riskFactor {
+	densities := make(map[string]float64)
+	for _, d := range report.Files {
+		densities[d.Name] = d.Density()
+	}
+
+	total := 0.0
+	for _, file := range files {
+		total += densities[file]
+	}
+	average := total / float64(len(files))
+	return riskFactor{
+		Name:        "Defect history",
+		Score:       math.Min(1, average*2) * riskFactorWeight,
+		Explanation: fmt.Sprintf("touched files have an average defect density of %.2f", average),
+	}
+}
+
+func printRisk(report *riskReport) {
+	fmt.Printf("Risk analysis for %s (%d commits, %d files):\n", report.Range, report.Commits, len(report.Files))
+	fmt.Printf("\nRisk score: %.0f/100 (%s)\n\n", report.Score(), report.Level())
+	for _, f := range report.Factors {
+		fmt.Printf("%s: %.1f/%.0f - %s\n", f.Name, f.Score, riskFactorWeight, f.Explanation)
+	}
+}
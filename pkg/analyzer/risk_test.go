@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestResolveRevRange(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	author := &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Now()}
+
+	first, err := commitFiles(repo, "first", author, map[string]string{"a": "1"})
+	if err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	var last []plumbing.Hash
+	for _, content := range []string{"2", "3"} {
+		h, err := commitFiles(repo, "next", author, map[string]string{"a": content})
+		if err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+		last = append(last, h)
+	}
+
+	commits, base, err := resolveRevRange(repo, first.String()+"..HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve range: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Hash != last[1] || commits[1].Hash != last[0] {
+		t.Errorf("Expected the 2 commits after the first one, got %v", commits)
+	}
+	if base == nil || base.Hash != first {
+		t.Errorf("Expected range based on the first commit, got %v", base)
+	}
+
+	commits, base, err = resolveRevRange(repo, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve revision: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Hash != last[1] || base.Hash != last[0] {
+		t.Errorf("Expected HEAD based on its parent, got %v based on %v", commits, base)
+	}
+}
+
+func TestResolveMergeBaseRange(t *testing.T) {
+	repo := createBranches(t)
+
+	commits, base, err := resolveRevRange(repo, "master...feature")
+	if err != nil {
+		t.Fatalf("Failed to resolve range: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "Continue feature" || commits[1].Message != "Start feature" {
+		t.Errorf("Expected the 2 feature commits, got %v", commits)
+	}
+	if base == nil || base.Message != "Initial commit" {
+		t.Errorf("Expected range based on the merge base, got %v", base)
+	}
+}
+
+func TestGetRiskIgnoresLaterCommits(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, commit := range []struct{ message, author, content string }{
+		{"Add calc", "Alice", "a\nb\nc\n"},
+		{"Change b", "Bob", "a\nB\nc\n"},
+		{"Fix wrong value in calc", "Carol", "a\nb2\nc\n"},
+	} {
+		author := &object.Signature{Name: commit.author, Email: commit.author + "@example.com", When: start.Add(time.Duration(i) * time.Hour)}
+		if _, err := commitFiles(repo, commit.message, author, map[string]string{"calc.go": commit.content}); err != nil {
+			t.Fatalf("Failed to create commit for tests: %v", err)
+		}
+	}
+
+	// The later fix blames the range, but must not count as its defect history
+	report, err := getRisk(repo, "HEAD~2..HEAD~1", regexp.MustCompile(DefaultFixPattern), Options{})
+	if err != nil {
+		t.Fatalf("Failed to compute risk: %v", err)
+	}
+	for _, f := range report.Factors {
+		if f.Name == "Defect history" && f.Score != 0 {
+			t.Errorf("Expected no defect history before the range, got %+v", f)
+		}
+	}
+}
+
+func TestRiskFactors(t *testing.T) {
+	files := []string{"a.go", "b.go"}
+	fileAuthors := map[string]map[string]bool{
+		"a.go": {"Alice": true, "Bob": true, "Carol": true},
+		"b.go": {"Bob": true},
+	}
+
+	if f := sizeRisk(999, 2); f.Score != riskFactorWeight {
+		t.Errorf("Expected maximum size risk for 999 lines, got %v", f.Score)
+	}
+	if f := hotspotRisk(files, map[string]bool{"a.go": true}); f.Score != riskFactorWeight/2 {
+		t.Errorf("Expected half hotspot risk, got %v", f.Score)
+	}
+	if f := ownershipRisk(files, fileAuthors); f.Score != 0.4*riskFactorWeight {
+		t.Errorf("Expected ownership risk for 2 owners on average, got %v", f.Score)
+	}
+	if f := experienceRisk(files, map[string]bool{"Alice": true}, fileAuthors); f.Score != riskFactorWeight/2 {
+		t.Errorf("Expected half experience risk, got %v", f.Score)
+	}
+	report := &defectReport{Files: []defectDensity{{Name: "a.go", Defects: 1, Commits: 2}}}
+	if f := defectRisk(files, report); f.Score != riskFactorWeight/2 {
+		t.Errorf("Expected half defect risk, got %v", f.Score)
+	}
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// semver is a semantic version parsed from a tag such as "v1.2.3"
+type semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// parseSemver parses a version with an optional "v" prefix and prerelease
+// suffix. Build metadata is ignored.
+func parseSemver(s string) (*semver, bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return &semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Prerelease: pre}, true
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// less reports whether v has lower precedence than other
+func (v semver) less(other semver) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	if v.Patch != other.Patch {
+		return v.Patch < other.Patch
+	}
+	// A prerelease has lower precedence than the release itself
+	if v.Prerelease == "" || other.Prerelease == "" {
+		return v.Prerelease != "" && other.Prerelease == ""
+	}
+	return v.Prerelease < other.Prerelease
+}
+
+// allowsBreakingChangesFrom reports whether moving from old to v may break
+// the API: either the major version was bumped or both are v0 releases,
+// which carry no compatibility guarantee
+func (v semver) allowsBreakingChangesFrom(old semver) bool {
+	return v.Major > old.Major || (v.Major == 0 && old.Major == 0)
+}
+
+// revisionVersion returns the semantic version of a revision, either parsed
+// from the revision itself or from the highest version tag pointing at it
+func revisionVersion(repo *git.Repository, rev string) (*semver, error) {
+	if v, ok := parseSemver(rev); ok {
+		return v, nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("Error resolving revision %s: %w", rev, err)
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting tags: %w", err)
+	}
+
+	var version *semver
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		v, ok := parseSemver(ref.Name().Short())
+		if !ok {
+			return nil
+		}
+		target, err := repo.ResolveRevision(plumbing.Revision(ref.Name().String()))
+		if err != nil || *target != *hash {
+			return nil
+		}
+		if version == nil || version.less(*v) {
+			version = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error iterating over tags: %w", err)
+	}
+	return version, nil
+}
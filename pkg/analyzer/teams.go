@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gopkg.in/yaml.v3"
+)
+
+// Team credited with the work of authors that belong to no team
+const noTeam = "(no team)"
+
+type team struct {
+	Name        string
+	Members     []string `yaml:"members"`     // Emails, email globs or author names
+	Directories []string `yaml:"directories"` // Directories owned by the team
+}
+
+// TeamMapping assigns authors and directories to teams, as read from a teams
+// file such as:
+//
+//	platform:
+//	  members: [alice@example.com, "*@infra.example.com"]
+//	  directories: [infra, pkg/platform]
+//	web:
+//	  members: [Bob]
+type TeamMapping struct {
+	Teams []team // Sorted by name
+}
+
+// LoadTeams reads a teams file
+func LoadTeams(file string) (*TeamMapping, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading teams file: %w", err)
+	}
+	mapping, err := parseTeams(content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing teams file %s: %w", file, err)
+	}
+	return mapping, nil
+}
+
+func parseTeams(content []byte) (*TeamMapping, error) {
+	var byName map[string]team
+	if err := yaml.Unmarshal(content, &byName); err != nil {
+		return nil, err
+	}
+	mapping := &TeamMapping{}
+	for name, t := range byName {
+		t.Name = name
+		for i, dir := range t.Directories {
+			t.Directories[i] = strings.Trim(dir, "/")
+		}
+		mapping.Teams = append(mapping.Teams, t)
+	}
+	sort.Slice(mapping.Teams, func(i, j int) bool {
+		return mapping.Teams[i].Name < mapping.Teams[j].Name
+	})
+	return mapping, nil
+}
+
+// TeamOf returns the first team, by name, with a member matching the author
+func (m *TeamMapping) TeamOf(name string, email string) string {
+	email = strings.ToLower(email)
+	for _, t := range m.Teams {
+		for _, member := range t.Members {
+			if strings.EqualFold(member, name) {
+				return t.Name
+			}
+			if matched, _ := path.Match(strings.ToLower(member), email); matched {
+				return t.Name
+			}
+		}
+	}
+	return noTeam
+}
+
+// OwnerOf returns the team owning the most specific directory containing
+// file, or an empty string when no team owns it
+func (m *TeamMapping) OwnerOf(file string) string {
+	owner, longest := "", -1
+	for _, t := range m.Teams {
+		for _, dir := range t.Directories {
+			if (dir == "" || file == dir || strings.HasPrefix(file, dir+"/")) && len(dir) > longest {
+				owner, longest = t.Name, len(dir)
+			}
+		}
+	}
+	return owner
+}
+
+// authorName returns the name analyses credit an author's work to: their
+// team when aggregating by team, otherwise their name
+func (o Options) authorName(name string, email string) string {
+	if o.Teams != nil {
+		return o.Teams.TeamOf(name, email)
+	}
+	return name
+}
+
+type teamActivity struct {
+	Name    string
+	Authors map[string]bool
+	Commits int
+	Added   int
+	Deleted int
+}
+
+type crossTeamChange struct {
+	Author  string // Team of the authors making the changes
+	Owner   string // Team owning the changed files
+	Commits int
+	Files   int // File changes
+}
+
+type teamsReport struct {
+	Teams     []teamActivity
+	CrossTeam []crossTeamChange
+}
+
+// AnalyzeTeams prints the activity of each team in the given repository and
+// the changes teams made to directories owned by other teams
+func AnalyzeTeams(repoPath string, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	teamStats(repo, opts)
+}
+
+func teamStats(repo *git.Repository, opts Options) {
+	if opts.Teams == nil {
+		log.Fatalf("Error getting team activity: no teams file loaded")
+	}
+	report, err := getTeamsReport(repo, opts)
+	if err != nil {
+		log.Fatalf("Error getting team activity: %v", err)
+	}
+	printTeamsReport(report)
+}
+
+// getTeamsReport aggregates the non-merge commits reachable from HEAD by the
+// team opts.Teams assigns their author to. A change to a file owned by
+// another team counts as a cross-team change.
+func getTeamsReport(repo *git.Repository, opts Options) (*teamsReport, error) {
+	mapping := opts.Teams
+	activity := make(map[string]*teamActivity)
+	crossTeam := make(map[[2]string]*crossTeamChange)
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		name := mapping.TeamOf(c.Author.Name, c.Author.Email)
+		a, ok := activity[name]
+		if !ok {
+			a = &teamActivity{Name: name, Authors: make(map[string]bool)}
+			activity[name] = a
+		}
+		a.Commits++
+		a.Authors[c.Author.Name] = true
+
+		stats, err := c.Stats()
+		if err != nil {
+			return fmt.Errorf("Error getting stats of commit %s: %w", c.Hash, err)
+		}
+		owners := make(map[string]int)
+		for _, stat := range stats {
+			a.Added += stat.Addition
+			a.Deleted += stat.Deletion
+			if owner := mapping.OwnerOf(stat.Name); owner != "" && owner != name {
+				owners[owner]++
+			}
+		}
+		for owner, files := range owners {
+			key := [2]string{name, owner}
+			change, ok := crossTeam[key]
+			if !ok {
+				change = &crossTeamChange{Author: name, Owner: owner}
+				crossTeam[key] = change
+			}
+			change.Commits++
+			change.Files += files
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &teamsReport{}
+	for _, a := range activity {
+		report.Teams = append(report.Teams, *a)
+	}
+	sort.Slice(report.Teams, func(i, j int) bool {
+		if report.Teams[i].Commits != report.Teams[j].Commits {
+			return report.Teams[i].Commits > report.Teams[j].Commits
+		}
+		return report.Teams[i].Name < report.Teams[j].Name
+	})
+	for _, change := range crossTeam {
+		report.CrossTeam = append(report.CrossTeam, *change)
+	}
+	sort.Slice(report.CrossTeam, func(i, j int) bool {
+		if report.CrossTeam[i].Commits != report.CrossTeam[j].Commits {
+			return report.CrossTeam[i].Commits > report.CrossTeam[j].Commits
+		}
+		if report.CrossTeam[i].Author != report.CrossTeam[j].Author {
+			return report.CrossTeam[i].Author < report.CrossTeam[j].Author
+		}
+		return report.CrossTeam[i].Owner < report.CrossTeam[j].Owner
+	})
+	return report, nil
+}
+
+func printTeamsReport(report *teamsReport) {
+	fmt.Println("Team activity:")
+	for _, t := range report.Teams {
+		fmt.Printf("%s: %d commits by %d authors, +%d/-%d lines\n", t.Name, t.Commits, len(t.Authors), t.Added, t.Deleted)
+	}
+
+	fmt.Println("\nCross-team changes (team editing directories owned by another team):")
+	if len(report.CrossTeam) == 0 {
+		fmt.Println("None")
+	}
+	for _, change := range report.CrossTeam {
+		fmt.Printf("%s -> %s: %d commits, %d file changes\n", change.Author, change.Owner, change.Commits, change.Files)
+	}
+}
@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const testTeams = `
+web:
+  members: [Bob]
+  directories: [web/]
+platform:
+  members: [alice@example.com, "*@infra.example.com"]
+  directories: [infra, web/build]
+`
+
+func TestParseTeams(t *testing.T) {
+	mapping, err := parseTeams([]byte(testTeams))
+	if err != nil {
+		t.Fatalf("Failed to parse teams: %v", err)
+	}
+	if len(mapping.Teams) != 2 || mapping.Teams[0].Name != "platform" {
+		t.Fatalf("Expected platform and web sorted by name, got %+v", mapping.Teams)
+	}
+
+	members := []struct {
+		name, email, expected string
+	}{
+		{"Alice", "ALICE@example.com", "platform"},
+		{"Dan", "dan@infra.example.com", "platform"},
+		{"Bob", "bob@example.com", "web"},
+		{"Carol", "carol@example.com", noTeam},
+	}
+	for _, m := range members {
+		if got := mapping.TeamOf(m.name, m.email); got != m.expected {
+			t.Errorf("TeamOf(%s, %s) = %s, want %s", m.name, m.email, got, m.expected)
+		}
+	}
+
+	files := map[string]string{
+		"web/app.js":     "web",
+		"web/build/x.sh": "platform",
+		"infra/main.tf":  "platform",
+		"infrastructure": "",
+		"README.md":      "",
+	}
+	for file, expected := range files {
+		if got := mapping.OwnerOf(file); got != expected {
+			t.Errorf("OwnerOf(%s) = %q, want %q", file, got, expected)
+		}
+	}
+
+	if _, err := parseTeams([]byte("web: [")); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
+
+func TestGetTeamsReport(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: when}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: when.Add(time.Hour)}
+	dan := &object.Signature{Name: "Dan", Email: "dan@infra.example.com", When: when.Add(2 * time.Hour)}
+
+	if _, err := commitFiles(repo, "add infra", alice, map[string]string{"infra/main.tf": "a\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "add web", bob, map[string]string{"web/app.js": "b\n", "infra/main.tf": "a\nb\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "fix web", dan, map[string]string{"web/app.js": "c\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+
+	mapping, err := parseTeams([]byte(testTeams))
+	if err != nil {
+		t.Fatalf("Failed to parse teams: %v", err)
+	}
+	opts := Options{Teams: mapping}
+	report, err := getTeamsReport(repo, opts)
+	if err != nil {
+		t.Fatalf("Failed to get teams report: %v", err)
+	}
+
+	if len(report.Teams) != 2 || report.Teams[0].Name != "platform" || report.Teams[0].Commits != 2 || len(report.Teams[0].Authors) != 2 {
+		t.Errorf("Expected 2 commits by 2 platform authors first, got %+v", report.Teams)
+	}
+	expected := []crossTeamChange{
+		{Author: "platform", Owner: "web", Commits: 1, Files: 1},
+		{Author: "web", Owner: "platform", Commits: 1, Files: 1},
+	}
+	if !reflect.DeepEqual(report.CrossTeam, expected) {
+		t.Errorf("got %+v, want %+v", report.CrossTeam, expected)
+	}
+
+	// Author statistics are aggregated by team when teams are given
+	commitCounts, _, err := getCommitCounts(repo, opts)
+	if err != nil {
+		t.Fatalf("Failed to count commits: %v", err)
+	}
+	if !reflect.DeepEqual(commitCounts, map[string]int{"platform": 2, "web": 1}) {
+		t.Errorf("Expected commits per team, got %v", commitCounts)
+	}
+	_, heatmaps, err := getHeatmaps(repo, nil, opts)
+	if err != nil {
+		t.Fatalf("Failed to get heatmaps: %v", err)
+	}
+	if len(heatmaps) != 2 || heatmaps[0].Name != "platform" || heatmaps[1].Name != "web" {
+		t.Errorf("Expected heatmaps of platform and web, got %+v", heatmaps)
+	}
+	tl, err := getTimeline(repo, PeriodWeek, SplitAuthor, opts)
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	if len(tl.Series) != 2 || tl.Series[0].Name != "platform" || tl.Series[1].Name != "web" {
+		t.Errorf("Expected timeline series of platform and web, got %+v", tl.Series)
+	}
+
+	// Co-authors from the same team count once
+	c := &object.Commit{
+		Author:  *alice,
+		Message: "Pair on infra\n\nCo-authored-by: Dan <dan@infra.example.com>\nCo-authored-by: Bob <bob@example.com>\n",
+	}
+	if got := commitParticipants(c, opts); !reflect.DeepEqual(got, []string{"platform", "web"}) {
+		t.Errorf("Expected platform and web as participants, got %v", got)
+	}
+}
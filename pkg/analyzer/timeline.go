@@ -0,0 +1,263 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Ways of splitting a timeline into several series
+const (
+	SplitNone      = ""
+	SplitAuthor    = "author"
+	SplitDirectory = "directory"
+)
+
+// Series of the commits without file changes, such as merges, when
+// splitting a timeline by directory
+const noChangesSeries = "(no changes)"
+
+// Characters of increasing height used to draw sparklines
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+type timelineBucket struct {
+	Period        string `json:"period"`
+	Commits       int    `json:"commits"`
+	ActiveAuthors int    `json:"active_authors"`
+	LinesAdded    int    `json:"lines_added"`
+	LinesDeleted  int    `json:"lines_deleted"`
+	FilesTouched  int    `json:"files_touched"`
+}
+
+type timelineSeries struct {
+	Name    string           `json:"name"`
+	Buckets []timelineBucket `json:"buckets"`
+}
+
+type timeline struct {
+	Bucket string           `json:"bucket"`
+	Split  string           `json:"split,omitempty"`
+	Series []timelineSeries `json:"series"`
+}
+
+// bucketActivity accumulates the activity of a series during one period
+type bucketActivity struct {
+	commits int
+	authors map[string]bool
+	added   int
+	deleted int
+	files   map[string]bool
+}
+
+// AnalyzeTimeline prints the activity of the given repository per period,
+// as a chart or as JSON
+func AnalyzeTimeline(repoPath string, bucket string, split string, asJSON bool, opts Options) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		log.Fatalf("Error opening repository: %v", err)
+	}
+
+	activityTimeline(repo, bucket, split, asJSON, opts)
+}
+
+func activityTimeline(repo *git.Repository, bucket string, split string, asJSON bool, opts Options) {
+	tl, err := getTimeline(repo, bucket, split, opts)
+	if err != nil {
+		log.Fatalf("Error getting timeline: %v", err)
+	}
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(tl); err != nil {
+			log.Fatalf("Error encoding timeline: %v", err)
+		}
+		return
+	}
+	printTimeline(tl)
+}
+
+// getTimeline buckets commits, active authors, changed lines and touched
+// files by the period the commits were authored in (in UTC), optionally
+// split into one series per author or per top-level directory. Commits count
+// in every directory they touch, and commits without file changes count in
+// a series of their own. Every series covers the same periods, including
+// periods without activity.
+func getTimeline(repo *git.Repository, bucket string, split string, opts Options) (*timeline, error) {
+	if !validPeriod(bucket) {
+		return nil, fmt.Errorf("Unsupported bucket %q", bucket)
+	}
+
+	activity := make(map[string]map[string]*bucketActivity)
+	record := func(series string, period string, author string) *bucketActivity {
+		if activity[series] == nil {
+			activity[series] = make(map[string]*bucketActivity)
+		}
+		a, ok := activity[series][period]
+		if !ok {
+			a = &bucketActivity{authors: make(map[string]bool), files: make(map[string]bool)}
+			activity[series][period] = a
+		}
+		a.authors[author] = true
+		return a
+	}
+
+	var first, last time.Time
+	err := forEachCommit(repo, opts, func(c *object.Commit) error {
+		when := c.Author.When.UTC()
+		if first.IsZero() || when.Before(first) {
+			first = when
+		}
+		if when.After(last) {
+			last = when
+		}
+		period := periodKey(when, bucket)
+
+		// Merge commits count as activity but their changes belong to the
+		// commits being merged
+		var stats object.FileStats
+		if c.NumParents() <= 1 {
+			var err error
+			stats, err = c.Stats()
+			if err != nil {
+				return fmt.Errorf("Error getting stats of commit %s: %w", c.Hash, err)
+			}
+		}
+
+		switch split {
+		case SplitAuthor:
+			addCommitActivity(record(opts.authorName(c.Author.Name, c.Author.Email), period, c.Author.Name), stats)
+		case SplitDirectory:
+			if len(stats) == 0 {
+				addCommitActivity(record(noChangesSeries, period, c.Author.Name), stats)
+				break
+			}
+			byDirectory := make(map[string]object.FileStats)
+			for _, stat := range stats {
+				dir := directoryAtDepth(stat.Name, 1)
+				byDirectory[dir] = append(byDirectory[dir], stat)
+			}
+			for dir, dirStats := range byDirectory {
+				addCommitActivity(record(dir, period, c.Author.Name), dirStats)
+			}
+		default:
+			addCommitActivity(record("All", period, c.Author.Name), stats)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tl := &timeline{Bucket: bucket, Split: split}
+	if first.IsZero() {
+		return tl, nil
+	}
+	periods := periodRange(first, last, bucket)
+	for name, byPeriod := range activity {
+		series := timelineSeries{Name: name}
+		for _, period := range periods {
+			b := timelineBucket{Period: period}
+			if a, ok := byPeriod[period]; ok {
+				b.Commits = a.commits
+				b.ActiveAuthors = len(a.authors)
+				b.LinesAdded = a.added
+				b.LinesDeleted = a.deleted
+				b.FilesTouched = len(a.files)
+			}
+			series.Buckets = append(series.Buckets, b)
+		}
+		tl.Series = append(tl.Series, series)
+	}
+	sort.Slice(tl.Series, func(i, j int) bool {
+		ci, cj := tl.Series[i].totalCommits(), tl.Series[j].totalCommits()
+		if ci != cj {
+			return ci > cj
+		}
+		return tl.Series[i].Name < tl.Series[j].Name
+	})
+	return tl, nil
+}
+
+func addCommitActivity(a *bucketActivity, stats object.FileStats) {
+	a.commits++
+	for _, stat := range stats {
+		a.added += stat.Addition
+		a.deleted += stat.Deletion
+		a.files[stat.Name] = true
+	}
+}
+
+func (s timelineSeries) totalCommits() int {
+	total := 0
+	for _, b := range s.Buckets {
+		total += b.Commits
+	}
+	return total
+}
+
+// sparkline draws one character per value, scaled to the largest value
+func sparkline(values []int) string {
+	maxValue := 0
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if maxValue == 0 || v == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		sb.WriteRune(sparks[v*(len(sparks)-1)/maxValue])
+	}
+	return sb.String()
+}
+
+func printTimeline(tl *timeline) {
+	fmt.Printf("Activity timeline (per %s):\n", tl.Bucket)
+	if len(tl.Series) == 0 {
+		fmt.Println("\nNo commits found.")
+		return
+	}
+
+	// A single series is drawn as a bar chart, several as sparklines
+	if len(tl.Series) == 1 {
+		series := tl.Series[0]
+		maxCommits := 0
+		for _, b := range series.Buckets {
+			if b.Commits > maxCommits {
+				maxCommits = b.Commits
+			}
+		}
+		fmt.Println()
+		for _, b := range series.Buckets {
+			width := b.Commits * 40 / maxCommits
+			bar := strings.Repeat("█", width) + strings.Repeat(" ", 40-width)
+			fmt.Printf("%s %s %d commits, %d authors, +%d/-%d lines, %d files\n",
+				b.Period, bar, b.Commits, b.ActiveAuthors, b.LinesAdded, b.LinesDeleted, b.FilesTouched)
+		}
+		return
+	}
+
+	buckets := tl.Series[0].Buckets
+	fmt.Printf("\nCommits from %s to %s by %s:\n", buckets[0].Period, buckets[len(buckets)-1].Period, tl.Split)
+	for _, series := range tl.Series {
+		var commits []int
+		added, deleted := 0, 0
+		for _, b := range series.Buckets {
+			commits = append(commits, b.Commits)
+			added += b.LinesAdded
+			deleted += b.LinesDeleted
+		}
+		fmt.Printf("%s %s %d commits, +%d/-%d lines\n", sparkline(commits), series.Name, series.totalCommits(), added, deleted)
+	}
+}
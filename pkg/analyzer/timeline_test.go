@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestGetTimeline(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	alice := &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)}
+	bob := &object.Signature{Name: "Bob", Email: "bob@example.com", When: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}
+
+	if _, err := commitFiles(repo, "add", alice, map[string]string{"src/a.go": "1\n2\n", "docs/a.md": "a\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+	if _, err := commitFiles(repo, "update", bob, map[string]string{"src/a.go": "1\nb\n"}); err != nil {
+		t.Fatalf("Failed to create commit for tests: %v", err)
+	}
+
+	tl, err := getTimeline(repo, PeriodMonth, SplitNone, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	expected := []timelineBucket{
+		{Period: "2024-01", Commits: 1, ActiveAuthors: 1, LinesAdded: 3, FilesTouched: 2},
+		{Period: "2024-02"},
+		{Period: "2024-03", Commits: 1, ActiveAuthors: 1, LinesAdded: 1, LinesDeleted: 1, FilesTouched: 1},
+	}
+	if len(tl.Series) != 1 || !reflect.DeepEqual(tl.Series[0].Buckets, expected) {
+		t.Errorf("got %+v, want a single series with %+v", tl.Series, expected)
+	}
+
+	tl, err = getTimeline(repo, PeriodMonth, SplitDirectory, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	if len(tl.Series) != 2 || tl.Series[0].Name != "src" || tl.Series[0].totalCommits() != 2 {
+		t.Errorf("Expected src with 2 commits first, got %+v", tl.Series)
+	}
+	if len(tl.Series[1].Buckets) != 3 {
+		t.Errorf("Expected every series to cover 3 months, got %+v", tl.Series[1])
+	}
+}
+
+func TestGetTimelineCountsCommitsWithoutChanges(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize in-memory repository: %v", err)
+	}
+	root := storeCommit(t, repo, 1, "Initial empty commit")
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), root)); err != nil {
+		t.Fatalf("Failed to set master: %v", err)
+	}
+
+	tl, err := getTimeline(repo, PeriodMonth, SplitDirectory, Options{})
+	if err != nil {
+		t.Fatalf("Failed to get timeline: %v", err)
+	}
+	if len(tl.Series) != 1 || tl.Series[0].Name != noChangesSeries || tl.Series[0].totalCommits() != 1 {
+		t.Errorf("Expected the empty commit in its own series, got %+v", tl.Series)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int{0, 1, 4, 8}); got != " ▁▄█" {
+		t.Errorf("sparkline() = %q, want %q", got, " ▁▄█")
+	}
+}